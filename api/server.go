@@ -23,6 +23,7 @@ type Server struct {
 	shutdownChannel     chan struct{}
 
 	onClientConnect func() []types.Broadcast
+	jsonHandlers    map[string]func() interface{}
 }
 
 func New(conf *types.Config, logger *zerolog.Logger, channel chan types.Broadcast) *Server {
@@ -33,6 +34,7 @@ func New(conf *types.Config, logger *zerolog.Logger, channel chan types.Broadcas
 		websocketClients: make(map[*websocket.Conn]bool),
 		waitGroup:        &sync.WaitGroup{},
 		shutdownChannel:  make(chan struct{}),
+		jsonHandlers:     make(map[string]func() interface{}),
 	}
 }
 
@@ -61,6 +63,10 @@ func (api *Server) Serve(port uint) error {
 		newConnectionCh <- conn
 	})
 
+	for path, handler := range api.jsonHandlers {
+		r.HandleFunc(path, api.jsonHandler(handler)).Methods(http.MethodGet)
+	}
+
 	r.PathPrefix("/").Handler(http.StripPrefix("/", http.FileServer(http.Dir("ui/build"))))
 
 	api.logger.Info().Msgf("Listening on port %d", port)
@@ -153,6 +159,27 @@ func (api *Server) OnClientConnect(f func() []types.Broadcast) {
 	api.onClientConnect = f
 }
 
+// HandleJSON serves the value returned by f as JSON on the given path, it must be called before Serve
+func (api *Server) HandleJSON(path string, f func() interface{}) {
+	api.jsonHandlers[path] = f
+}
+
+func (api *Server) jsonHandler(f func() interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		jsonData, err := json.Marshal(f())
+		if err != nil {
+			api.logger.Error().Err(err).Str("Path", r.URL.Path).Msg("Error marshaling response")
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(jsonData); err != nil {
+			api.logger.Error().Err(err).Str("Path", r.URL.Path).Msg("Error writing response")
+		}
+	}
+}
+
 func (api *Server) websocketListen(conn *websocket.Conn) {
 	defer func() {
 		api.websocketClientDisconnect(conn)
@@ -18,14 +18,52 @@ var conf = &types.Config{}
 
 func init() {
 	flag.StringVar(&conf.RdsClusterName, "rdsClusterName", "", "RDS cluster name")
+	flag.StringVar(&conf.ClustersFile, "clustersFile", "", "YAML list of cluster definitions managed by this process, each overriding the flags with its own settings")
+	flag.BoolVar(&conf.DryRun, "dryRun", false, "Run the scaling decisions and report the planned actions without changing the cluster")
 	flag.StringVar(&conf.InstanceNamePrefix, "instanceNamePrefix", "predictive-autoscaling-", "Prefix for reader instance names")
 	flag.StringVar(&conf.AwsRegion, "awsRegion", "", "AWS region")
 
-	flag.Float64Var(&conf.TargetCpuUtil, "targetCpuUtilization", 70.0, "Target CPU utilization percentage")
-	flag.StringVar(&conf.BoostHours, "boostHours", "", "Comma-separated list of hours to boost minInstances")
+	flag.Float64Var(&conf.TargetCpuUtil, "targetCpuUtilization", 70.0, "Target CPU utilization percentage, readers are added above it")
+	flag.Float64Var(&conf.ScaleInCpuUtil, "scaleInCpuUtilization", 0, "Readers are only removed if the projected CPU utilization afterwards stays below this percentage, 0 scales in at the target")
+	flag.Float64Var(&conf.TargetConnections, "targetConnections", 0, "Target database connections per instance, 0 disables scaling on connections")
+	flag.Float64Var(&conf.TargetReplicaLag, "targetReplicaLag", 0, "Target Aurora replica lag in milliseconds, 0 disables scaling on replica lag")
+	flag.Float64Var(&conf.TargetReadIOPS, "targetReadIOPS", 0, "Target read IOPS per instance, 0 disables scaling on read IOPS")
+	flag.Float64Var(&conf.TargetDBLoad, "targetDBLoad", 0, "Target DBLoad (average active sessions) per instance, 0 disables scaling on DBLoad")
+	flag.StringVar(&conf.BoostHours, "boostHours", "", "Comma-separated list of UTC hours to boost minInstances by one (deprecated, use scheduleFile)")
+	flag.StringVar(&conf.ScheduleFile, "scheduleFile", "", "YAML file with scheduled capacity rules (cron or days with start/end, timezone, min/max instances)")
 	flag.DurationVar(&conf.PlanAheadTime, "planAheadTime", 10*time.Minute, "The time to plan ahead when looking up prior CPU utilization")
+	flag.DurationVar(&conf.ScaleOutCooldown, "scaleOutCooldown", 0, "Minimum time between new readers becoming available and the next scale out")
+	flag.DurationVar(&conf.ScaleInCooldown, "scaleInCooldown", 5*time.Minute, "Minimum time after any scaling operation before the next scale in")
+	flag.DurationVar(&conf.ScaleInStabilizationWindow, "scaleInStabilizationWindow", 5*time.Minute, "Scale in only happens if the optimal size stayed lower for this whole window")
+	flag.StringVar(&conf.TimeZone, "timeZone", "UTC", "Business time zone, seasonal lookups and calendar days follow its wall-clock time")
+	flag.StringVar(&conf.Predictor, "predictor", scaler.PredictorWeekAgo, "Strategy used to predict the cluster status (weekAgo, seasonal, holtWinters, trend, ensemble)")
+	flag.UintVar(&conf.SeasonalWeeks, "seasonalWeeks", 4, "Number of past weeks the seasonal predictor looks back on")
+	flag.StringVar(&conf.SeasonalWeights, "seasonalWeights", "", "Comma-separated weights for the past weeks, most recent first (default: equal weights)")
+	flag.StringVar(&conf.SeasonalAggregation, "seasonalAggregation", scaler.AggregationWeighted, "How the seasonal predictor combines past weeks (weighted, median)")
+	flag.DurationVar(&conf.HoltWintersHistory, "holtWintersHistory", 21*24*time.Hour, "History the Holt-Winters model is fitted on, must exceed two weeks")
+	flag.Float64Var(&conf.HoltWintersAlpha, "holtWintersAlpha", 0.1, "Holt-Winters smoothing factor for the level")
+	flag.Float64Var(&conf.HoltWintersBeta, "holtWintersBeta", 0.01, "Holt-Winters smoothing factor for the trend")
+	flag.Float64Var(&conf.HoltWintersGamma, "holtWintersGamma", 0.2, "Holt-Winters smoothing factor for the daily seasonality")
+	flag.Float64Var(&conf.HoltWintersDelta, "holtWintersDelta", 0.2, "Holt-Winters smoothing factor for the weekly seasonality")
+	flag.DurationVar(&conf.TrendWindow, "trendWindow", 0, "Recent window (e.g. 30m) extrapolated by planAheadTime as an additional scaling signal, 0 disables it")
+	flag.StringVar(&conf.TrendMethod, "trendMethod", scaler.TrendMethodLinear, "Regression used to extrapolate the recent trend (linear, theilSen)")
+	flag.StringVar(&conf.EnsemblePredictors, "ensemblePredictors", "weekAgo,seasonal", "Comma-separated list of predictors blended by the ensemble predictor")
+	flag.DurationVar(&conf.EnsembleWindow, "ensembleWindow", 6*time.Hour, "Trailing window the prediction error of the ensemble members is measured over")
+	flag.StringVar(&conf.CalendarFile, "calendarFile", "", "ICS or YAML file with days to ignore, substitute or override when predicting")
 	flag.UintVar(&conf.MinInstances, "minInstances", 2, "Minimum number of readers required in the cluster")
 	flag.UintVar(&conf.MaxInstances, "maxInstances", 5, "Maximum number of readers allowed in the cluster")
+	flag.BoolVar(&conf.ExcludeWriter, "excludeWriter", false, "Size the cluster on the readers only, for applications that send all reads to the reader endpoint")
+	flag.StringVar(&conf.ReaderInstanceClass, "readerInstanceClass", "", "Instance class of new readers, e.g. db.r6g.large (default: the writer's class)")
+	flag.StringVar(&conf.ReaderInstanceClassFallbacks, "readerInstanceClassFallbacks", "", "Comma-separated instance classes tried in order if a reader can't be created with readerInstanceClass")
+	flag.StringVar(&conf.VerticalScalingClasses, "verticalScalingClasses", "", "Comma-separated instance classes from smallest to largest, managed readers are moved along them once maxInstances is reached, empty disables vertical scaling")
+	flag.StringVar(&conf.CustomEndpoints, "customEndpoints", "", "Comma-separated custom endpoints with static members that new readers are added to and removed from before deletion")
+	flag.StringVar(&conf.AvailabilityZones, "availabilityZones", "", "Comma-separated availability zones readers are balanced across (default: the AZs of the cluster)")
+	flag.DurationVar(&conf.CapacityBlacklistDuration, "capacityBlacklistDuration", 30*time.Minute, "How long an instance class and availability zone without capacity is skipped for new readers")
+	flag.Int64Var(&conf.PromotionTier, "promotionTier", 15, "Failover priority of new readers from 0 (highest) to 15 (lowest)")
+	flag.StringVar(&conf.FailoverAction, "failoverAction", scaler.FailoverActionFailback, "What to do once a managed reader became the writer (failback: fail over to a permanent reader, adopt: keep it permanently, none)")
+	flag.StringVar(&conf.ScalingMode, "scalingMode", scaler.ScalingModeInstances, "How capacity is added (instances: provisioned readers, serverless: the Serverless v2 minimum capacity, mixed: provisioned and db.serverless readers)")
+	flag.Float64Var(&conf.ServerlessMinCapacity, "serverlessMinCapacity", 0.5, "Lowest Serverless v2 minimum capacity in ACUs the scaler sets outside of peaks")
+	flag.DurationVar(&conf.BurstDuration, "burstDuration", time.Hour, "In mixed scaling mode, capacity still needed this long after planAheadTime is added as provisioned readers, shorter spikes as serverless readers")
 
 	flag.UintVar(&conf.ServerPort, "serverPort", 8041, "Port for the ui server")
 
@@ -53,16 +91,31 @@ func main() {
 	// Create broadcast channel
 	broadcast := make(chan types.Broadcast)
 
-	// Create and start the scaler
-	rdsScaler, err := scaler.New(conf, logger, awsSession, broadcast)
-	if err != nil {
-		logger.Error().Err(err).Msg("Failed to create scaler")
-		return
+	configs := []*types.Config{conf}
+	if conf.ClustersFile != "" {
+		configs, err = scaler.LoadClusterConfigs(conf.ClustersFile, *conf)
+		if err != nil {
+			logger.Error().Err(err).Msg("Failed to load cluster definitions")
+			return
+		}
+	}
+
+	// Create a scaler per cluster
+	rdsScalers := make([]*scaler.Scaler, 0, len(configs))
+	for _, clusterConf := range configs {
+		clusterLogger := logger.With().Str("Cluster", clusterConf.RdsClusterName).Logger()
+		rdsScaler, err := scaler.New(clusterConf, &clusterLogger, awsSession, broadcast)
+		if err != nil {
+			logger.Error().Err(err).Str("Cluster", clusterConf.RdsClusterName).Msg("Failed to create scaler")
+			return
+		}
+		rdsScalers = append(rdsScalers, rdsScaler)
 	}
 
 	// Create and start the API server
 	apiServer := api.New(conf, logger, broadcast)
-	apiServer.OnClientConnect(initialBroadcasts(rdsScaler))
+	apiServer.OnClientConnect(initialBroadcasts(configs, rdsScalers))
+	registerClusterHandlers(apiServer, configs, rdsScalers)
 
 	go func() {
 		err = apiServer.Serve(conf.ServerPort)
@@ -71,7 +124,9 @@ func main() {
 		}
 	}()
 
-	rdsScaler.Run()
+	for _, rdsScaler := range rdsScalers {
+		go rdsScaler.Run()
+	}
 
 	// Set up a channel to capture termination signals
 	sigCh := make(chan os.Signal, 1)
@@ -83,26 +138,66 @@ func main() {
 	// Handle the termination signal by initiating graceful shutdown
 	logger.Info().Msg("Received termination signal. Initiating graceful shutdown...")
 
-	// Stop the API server and the scaler
+	// Stop the API server and the scalers
 	apiServer.Stop()
-	rdsScaler.Stop()
+	for _, rdsScaler := range rdsScalers {
+		rdsScaler.Stop()
+	}
 
 	logger.Info().Msg("Shutdown complete. Exiting.")
 }
 
-func initialBroadcasts(rdsScaler *scaler.Scaler) func() []types.Broadcast {
+// registerClusterHandlers serves the status of each cluster below /api/clusters/<name>
+func registerClusterHandlers(apiServer *api.Server, configs []*types.Config, rdsScalers []*scaler.Scaler) {
+	clusterNames := make([]string, 0, len(configs))
+	for _, clusterConf := range configs {
+		clusterNames = append(clusterNames, clusterConf.RdsClusterName)
+	}
+	apiServer.HandleJSON("/api/clusters", func() interface{} {
+		return clusterNames
+	})
+
+	for i, rdsScaler := range rdsScalers {
+		rdsScaler := rdsScaler
+		clusterConf := configs[i]
+		prefix := "/api/clusters/" + clusterConf.RdsClusterName
+
+		apiServer.HandleJSON(prefix+"/config", func() interface{} {
+			return clusterConf
+		})
+		apiServer.HandleJSON(prefix+"/status", func() interface{} {
+			return rdsScaler.GetClusterStatus()
+		})
+		apiServer.HandleJSON(prefix+"/accuracy", func() interface{} {
+			return rdsScaler.GetPredictionAccuracy()
+		})
+		apiServer.HandleJSON(prefix+"/history", func() interface{} {
+			return rdsScaler.GetClusterStatusHistory(24 * time.Hour)
+		})
+		apiServer.HandleJSON(prefix+"/predictionHistory", func() interface{} {
+			return rdsScaler.GetClusterStatusPredictionHistory(24 * time.Hour)
+		})
+	}
+}
+
+func initialBroadcasts(configs []*types.Config, rdsScalers []*scaler.Scaler) func() []types.Broadcast {
 	return func() []types.Broadcast {
 		var broadcasts []types.Broadcast
-		broadcasts = append(broadcasts, types.Broadcast{MessageType: "config", Data: conf})
+		for i, rdsScaler := range rdsScalers {
+			cluster := configs[i].RdsClusterName
+			broadcasts = append(broadcasts, types.Broadcast{MessageType: "config", Cluster: cluster, Data: configs[i]})
 
-		clusterStatusHistory := rdsScaler.GetClusterStatusHistory(24 * time.Hour)
-		if clusterStatusHistory != nil {
-			broadcasts = append(broadcasts, types.Broadcast{MessageType: "clusterStatusHistory", Data: clusterStatusHistory})
-		}
+			clusterStatusHistory := rdsScaler.GetClusterStatusHistory(24 * time.Hour)
+			if clusterStatusHistory != nil {
+				broadcasts = append(broadcasts, types.Broadcast{MessageType: "clusterStatusHistory", Cluster: cluster, Data: clusterStatusHistory})
+			}
+
+			clusterStatusPredictionHistory := rdsScaler.GetClusterStatusPredictionHistory(24 * time.Hour)
+			if clusterStatusPredictionHistory != nil {
+				broadcasts = append(broadcasts, types.Broadcast{MessageType: "clusterStatusPredictionHistory", Cluster: cluster, Data: clusterStatusPredictionHistory})
+			}
 
-		clusterStatusPredictionHistory := rdsScaler.GetClusterStatusPredictionHistory(24 * time.Hour)
-		if clusterStatusPredictionHistory != nil {
-			broadcasts = append(broadcasts, types.Broadcast{MessageType: "clusterStatusPredictionHistory", Data: clusterStatusPredictionHistory})
+			broadcasts = append(broadcasts, types.Broadcast{MessageType: "predictionAccuracy", Cluster: cluster, Data: rdsScaler.GetPredictionAccuracy()})
 		}
 
 		return broadcasts
@@ -0,0 +1,123 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"predictive-rds-scaler/types"
+	"strconv"
+	"strings"
+)
+
+const (
+	ServerlessInstanceClass = "db.serverless"
+
+	// CPU capacity of one Aurora capacity unit
+	vcpusPerACU = 0.25
+)
+
+// vCPUs of the instance sizes that don't follow the <n>xlarge pattern
+var instanceSizeVCPUs = map[string]float64{
+	"micro":  2,
+	"small":  2,
+	"medium": 2,
+	"large":  2,
+	"xlarge": 4,
+}
+
+// InstanceClassVCPUs derives the number of vCPUs from an instance class like db.r6g.2xlarge
+func InstanceClassVCPUs(instanceClass string) (float64, error) {
+	parts := strings.Split(instanceClass, ".")
+	if len(parts) != 3 || parts[0] != "db" {
+		return 0, fmt.Errorf("unknown instance class: %s", instanceClass)
+	}
+
+	size := parts[2]
+	if vcpus, ok := instanceSizeVCPUs[size]; ok {
+		return vcpus, nil
+	}
+
+	multiplier, err := strconv.Atoi(strings.TrimSuffix(size, "xlarge"))
+	if err != nil || !strings.HasSuffix(size, "xlarge") || multiplier <= 0 {
+		return 0, fmt.Errorf("unknown instance size: %s", instanceClass)
+	}
+	return float64(multiplier) * instanceSizeVCPUs["xlarge"], nil
+}
+
+// InstanceCapacityVCPUs returns the vCPUs of an instance class. Serverless v2 instances count with
+// their maximum capacity, which their CPU utilization is relative to.
+func InstanceCapacityVCPUs(instanceClass string, serverlessMaxCapacity float64) (float64, error) {
+	if instanceClass == ServerlessInstanceClass {
+		if serverlessMaxCapacity <= 0 {
+			return 0, fmt.Errorf("unknown Serverless v2 capacity")
+		}
+		return serverlessMaxCapacity * vcpusPerACU, nil
+	}
+	return InstanceClassVCPUs(instanceClass)
+}
+
+// SetReaderVCPUs sets the capacity of a newly created reader, which clusters with a known capacity
+// are scaled in units of
+func (m *Metrics) SetReaderVCPUs(vcpus float64) {
+	m.readerVCPUsMutex.Lock()
+	defer m.readerVCPUsMutex.Unlock()
+	m.readerVCPUs = vcpus
+}
+
+func (m *Metrics) getReaderVCPUs() float64 {
+	m.readerVCPUsMutex.RLock()
+	defer m.readerVCPUsMutex.RUnlock()
+	return m.readerVCPUs
+}
+
+// isCapacityWeighted reports whether the size of the cluster can be calculated in vCPUs
+func (m *Metrics) isCapacityWeighted(status *types.ClusterStatus) bool {
+	return status.TotalVCPUs > 0 && m.getReaderVCPUs() > 0
+}
+
+// CalculateCapacityClusterSize calculates the vCPUs needed to keep the load at the target and
+// translates the difference to the current capacity into readers of the new reader class. Like
+// CalculateOptimalClusterSize it only scales in once the remaining capacity stays below the
+// scale-in target.
+func (m *Metrics) CalculateCapacityClusterSize(status *types.ClusterStatus, minReaders uint) uint {
+	load := status.AverageCPUUtilization * status.TotalVCPUs
+	neededVCPUs := load / m.config.TargetCpuUtil
+	readerVCPUs := m.getReaderVCPUs()
+
+	var numberOfServers uint
+	if neededVCPUs > status.TotalVCPUs {
+		additionalReaders := uint(math.Ceil((neededVCPUs - status.TotalVCPUs) / readerVCPUs))
+		numberOfServers = status.CurrentActiveReaders + additionalReaders
+	} else {
+		if scaleInCPUUtilization := m.config.ScaleInCpuUtil; scaleInCPUUtilization > 0 {
+			neededVCPUs = load / scaleInCPUUtilization
+		}
+		removableReaders := uint(math.Max(0, math.Floor((status.TotalVCPUs-neededVCPUs)/readerVCPUs)))
+		numberOfServers = status.CurrentActiveReaders - min(removableReaders, status.CurrentActiveReaders)
+	}
+
+	return max(minReaders, min(numberOfServers, m.config.MaxInstances))
+}
+
+// projectedCPUUtilization returns the average CPU utilization after changing the number of readers,
+// readers are added or removed in units of the new reader class if the capacity is known
+func (m *Metrics) projectedCPUUtilization(status *types.ClusterStatus, newReaderCount uint) float64 {
+	if !m.isCapacityWeighted(status) {
+		return ProjectedUtilization(status.AverageCPUUtilization, status.CurrentActiveReaders, newReaderCount)
+	}
+
+	capacity := status.TotalVCPUs + (float64(newReaderCount)-float64(status.CurrentActiveReaders))*m.getReaderVCPUs()
+	if newReaderCount == 0 || capacity <= 0 {
+		return math.Inf(1)
+	}
+	return status.AverageCPUUtilization * status.TotalVCPUs / capacity
+}
+
+// ProjectedCapacityUtilization returns the average CPU utilization after changing the capacity of
+// the cluster by the given vCPUs, e.g. by changing the class of an instance
+func (m *Metrics) ProjectedCapacityUtilization(status *types.ClusterStatus, vcpuDelta float64) float64 {
+	capacity := status.TotalVCPUs + vcpuDelta
+	if status.TotalVCPUs == 0 || capacity <= 0 {
+		return math.Inf(1)
+	}
+	return status.AverageCPUUtilization * status.TotalVCPUs / capacity
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/rs/zerolog"
 	"math"
 	"predictive-rds-scaler/types"
+	"sync"
 	"time"
 )
 
@@ -18,10 +19,16 @@ type Metrics struct {
 	config types.Config
 	logger *zerolog.Logger
 	client *cloudwatch.CloudWatch
+
+	// set by the scaler and read by API handlers calculating the size of past statuses
+	readerVCPUsMutex sync.RWMutex
+	readerVCPUs      float64
 }
 
 func New(config types.Config, logger *zerolog.Logger, awsSession *session.Session) *Metrics {
-	client := cloudwatch.New(awsSession)
+	client := cloudwatch.New(awsSession, &aws.Config{
+		Region: aws.String(config.AwsRegion),
+	})
 
 	return &Metrics{
 		config: config,
@@ -30,6 +37,12 @@ func New(config types.Config, logger *zerolog.Logger, awsSession *session.Sessio
 	}
 }
 
+// SameTimeWeeksAgo returns the same wall-clock time in the given location the given number of weeks
+// earlier, which differs from a multiple of 168 hours if a daylight-saving switch lies in between
+func SameTimeWeeksAgo(t time.Time, weeks int, location *time.Location) time.Time {
+	return t.In(location).AddDate(0, 0, -7*weeks).In(time.UTC)
+}
+
 func (m *Metrics) GetCurrentInstanceUtilization(instance *rds.DBInstance) (float64, error) {
 	var (
 		metricValue       = 0.0
@@ -47,12 +60,11 @@ func (m *Metrics) GetCurrentInstanceUtilization(instance *rds.DBInstance) (float
 	return metricValue, nil
 }
 
-func (m *Metrics) GetHistoricClusterStatus(window time.Duration) (*types.ClusterStatus, error) {
-	var lastWeek = time.Now().
-		In(time.UTC).Add(-7 * 24 * time.Hour).Truncate(time.Second * 10)
-	var rangeEnd = lastWeek.Add(window)
+func (m *Metrics) GetHistoricClusterStatusAt(start time.Time, window time.Duration) (*types.ClusterStatus, error) {
+	var rangeStart = start.In(time.UTC).Truncate(time.Second * 10)
+	var rangeEnd = rangeStart.Add(window)
 
-	statusHistory, err := m.GetClusterStatus(lastWeek, rangeEnd, window)
+	statusHistory, err := m.GetClusterStatus(rangeStart, rangeEnd, window)
 	if err != nil {
 		return nil, err
 	}
@@ -75,12 +87,7 @@ func (m *Metrics) GetClusterStatus(start time.Time, end time.Time, window time.D
 				Metric: &cloudwatch.Metric{
 					Namespace:  aws.String("AWS/RDS"),
 					MetricName: aws.String("CPUUtilization"),
-					Dimensions: []*cloudwatch.Dimension{
-						{
-							Name:  aws.String("DBClusterIdentifier"),
-							Value: aws.String(m.config.RdsClusterName),
-						},
-					},
+					Dimensions: m.clusterDimensions(),
 				},
 				Period: aws.Int64(int64(window.Seconds())),
 				Stat:   aws.String("Average"),
@@ -93,12 +100,7 @@ func (m *Metrics) GetClusterStatus(start time.Time, end time.Time, window time.D
 				Metric: &cloudwatch.Metric{
 					Namespace:  aws.String("AWS/RDS"),
 					MetricName: aws.String("CPUUtilization"),
-					Dimensions: []*cloudwatch.Dimension{
-						{
-							Name:  aws.String("DBClusterIdentifier"),
-							Value: aws.String(m.config.RdsClusterName),
-						},
-					},
+					Dimensions: m.clusterDimensions(),
 				},
 				Period: aws.Int64(int64(window.Seconds())),
 				Stat:   aws.String("SampleCount"),
@@ -111,6 +113,9 @@ func (m *Metrics) GetClusterStatus(start time.Time, end time.Time, window time.D
 		},
 	}
 
+	scalingMetricQueries, scalingMetricIds := m.clusterMetricQueries(window)
+	metricQueries = append(metricQueries, scalingMetricQueries...)
+
 	input := &cloudwatch.GetMetricDataInput{
 		MetricDataQueries: metricQueries,
 		StartTime:         aws.Time(start.Truncate(10 * time.Second)),
@@ -130,13 +135,56 @@ func (m *Metrics) GetClusterStatus(start time.Time, end time.Time, window time.D
 			Timestamp:             *resp.MetricDataResults[0].Timestamps[i],
 			CurrentActiveReaders:  uint(*resp.MetricDataResults[1].Values[i]),
 			AverageCPUUtilization: *resp.MetricDataResults[0].Values[i],
-			OptimalSize:           m.CalculateOptimalClusterSize(*resp.MetricDataResults[0].Values[i], uint(*resp.MetricDataResults[1].Values[i]), m.config.MinInstances),
 		}
 	}
 
+	// the scaling metrics may lack data points, match them by timestamp
+	for _, result := range resp.MetricDataResults {
+		metricName, ok := scalingMetricIds[aws.StringValue(result.Id)]
+		if !ok {
+			continue
+		}
+
+		values := make(map[time.Time]float64, len(result.Values))
+		for j, timestamp := range result.Timestamps {
+			values[*timestamp] = *result.Values[j]
+		}
+
+		for _, status := range statusHistory {
+			if value, ok := values[status.Timestamp]; ok {
+				if status.Metrics == nil {
+					status.Metrics = make(map[string]float64)
+				}
+				status.Metrics[metricName] = value
+			}
+		}
+	}
+
+	for _, status := range statusHistory {
+		status.OptimalSize = m.CalculateRequiredClusterSize(status, m.config.MinInstances)
+	}
+
 	return statusHistory, nil
 }
 
+// clusterDimensions selects the cluster-wide metrics, limited to the readers if the writer is
+// excluded from the capacity calculations
+func (m *Metrics) clusterDimensions() []*cloudwatch.Dimension {
+	dimensions := []*cloudwatch.Dimension{
+		{
+			Name:  aws.String("DBClusterIdentifier"),
+			Value: aws.String(m.config.RdsClusterName),
+		},
+	}
+	if m.config.ExcludeWriter {
+		dimensions = append(dimensions, &cloudwatch.Dimension{
+			Name:  aws.String("Role"),
+			Value: aws.String("READER"),
+		})
+	}
+	return dimensions
+}
+
 func (m *Metrics) getMetricData(instanceIdentifier, metricName string, window time.Duration) (float64, error) {
 	metricInput := &cloudwatch.GetMetricDataInput{
 		MetricDataQueries: []*cloudwatch.MetricDataQuery{
@@ -176,8 +224,26 @@ func (m *Metrics) getMetricData(instanceIdentifier, metricName string, window ti
 	return 0, fmt.Errorf("no %s data available", metricName)
 }
 
+// CalculateOptimalClusterSize scales out once the utilization exceeds the target, but only scales in
+// once the utilization after removing readers stays below the scale-in target. In between the
+// current size is kept, so clusters hovering around the target don't oscillate.
 func (m *Metrics) CalculateOptimalClusterSize(utilization float64, currentReaderCount uint, minReaders uint) uint {
 	targetAverageCPUUtilization := m.config.TargetCpuUtil
-	numberOfServers := uint(math.Ceil(utilization * float64(currentReaderCount) / targetAverageCPUUtilization))
+	load := utilization * float64(currentReaderCount)
+	numberOfServers := uint(math.Ceil(load / targetAverageCPUUtilization))
+
+	if scaleInCPUUtilization := m.config.ScaleInCpuUtil; scaleInCPUUtilization > 0 && numberOfServers <= currentReaderCount {
+		// the fewest readers that keep the projected utilization below the scale-in target
+		numberOfServers = min(currentReaderCount, uint(math.Ceil(load/scaleInCPUUtilization)))
+	}
+
 	return max(minReaders, min(numberOfServers, m.config.MaxInstances))
 }
+
+// ProjectedUtilization returns the average utilization after changing the number of readers
+func ProjectedUtilization(utilization float64, currentReaderCount uint, newReaderCount uint) float64 {
+	if newReaderCount == 0 {
+		return math.Inf(1)
+	}
+	return utilization * float64(currentReaderCount) / float64(newReaderCount)
+}
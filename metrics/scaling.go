@@ -0,0 +1,198 @@
+package metrics
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"math"
+	"predictive-rds-scaler/types"
+	"sort"
+	"time"
+)
+
+// Metrics besides CPUUtilization the cluster size can be driven by. Each target is the average
+// value per instance the scaler aims for, except for the replica lag, which more readers don't
+// reduce and which is a threshold instead.
+const (
+	MetricDatabaseConnections = "DatabaseConnections"
+	MetricAuroraReplicaLag    = "AuroraReplicaLag"
+	MetricReadIOPS            = "ReadIOPS"
+	MetricDBLoad              = "DBLoad"
+)
+
+// ScalingTargets returns the enabled scaling metrics and their targets
+func (m *Metrics) ScalingTargets() map[string]float64 {
+	targets := make(map[string]float64)
+	for metricName, target := range map[string]float64{
+		MetricDatabaseConnections: m.config.TargetConnections,
+		MetricAuroraReplicaLag:    m.config.TargetReplicaLag,
+		MetricReadIOPS:            m.config.TargetReadIOPS,
+		MetricDBLoad:              m.config.TargetDBLoad,
+	} {
+		if target > 0 {
+			targets[metricName] = target
+		}
+	}
+	return targets
+}
+
+func (m *Metrics) scalingMetricNames() []string {
+	targets := m.ScalingTargets()
+	metricNames := make([]string, 0, len(targets))
+	for metricName := range targets {
+		metricNames = append(metricNames, metricName)
+	}
+	sort.Strings(metricNames)
+	return metricNames
+}
+
+// GetCurrentInstanceMetrics returns the current values of the enabled scaling metrics of an
+// instance, metrics without data points are left out
+func (m *Metrics) GetCurrentInstanceMetrics(instance *rds.DBInstance) (map[string]float64, error) {
+	metricNames := m.scalingMetricNames()
+	values := make(map[string]float64, len(metricNames))
+
+	if len(metricNames) == 0 || *instance.DBInstanceStatus != "available" {
+		return values, nil
+	}
+
+	window := periodInterval * time.Second
+	metricQueries := make([]*cloudwatch.MetricDataQuery, 0, len(metricNames))
+	for i, metricName := range metricNames {
+		metricQueries = append(metricQueries, metricQuery(fmt.Sprintf("metric%d", i), metricName, instanceDimensions(*instance.DBInstanceIdentifier), window))
+	}
+
+	metricDataOutput, err := m.client.GetMetricData(&cloudwatch.GetMetricDataInput{
+		MetricDataQueries: metricQueries,
+		StartTime:         aws.Time(time.Now().In(time.UTC).Add(-1 * window)),
+		EndTime:           aws.Time(time.Now().In(time.UTC)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range metricDataOutput.MetricDataResults {
+		var index int
+		if _, err := fmt.Sscanf(aws.StringValue(result.Id), "metric%d", &index); err != nil || len(result.Values) == 0 {
+			continue
+		}
+		values[metricNames[index]] = aws.Float64Value(result.Values[0])
+	}
+
+	return values, nil
+}
+
+// clusterMetricQueries returns the queries for the cluster-wide averages of the enabled scaling
+// metrics, their ids map back to the metric names
+func (m *Metrics) clusterMetricQueries(window time.Duration) ([]*cloudwatch.MetricDataQuery, map[string]string) {
+	metricNames := m.scalingMetricNames()
+	metricQueries := make([]*cloudwatch.MetricDataQuery, 0, len(metricNames))
+	ids := make(map[string]string, len(metricNames))
+
+	for i, metricName := range metricNames {
+		id := fmt.Sprintf("metric%d", i)
+		metricQueries = append(metricQueries, metricQuery(id, metricName, m.clusterDimensions(), window))
+		ids[id] = metricName
+	}
+	return metricQueries, ids
+}
+
+func instanceDimensions(instanceIdentifier string) []*cloudwatch.Dimension {
+	return []*cloudwatch.Dimension{
+		{
+			Name:  aws.String("DBInstanceIdentifier"),
+			Value: aws.String(instanceIdentifier),
+		},
+	}
+}
+
+func metricQuery(id, metricName string, dimensions []*cloudwatch.Dimension, window time.Duration) *cloudwatch.MetricDataQuery {
+	return &cloudwatch.MetricDataQuery{
+		Id: aws.String(id),
+		MetricStat: &cloudwatch.MetricStat{
+			Metric: &cloudwatch.Metric{
+				Namespace:  aws.String("AWS/RDS"),
+				MetricName: aws.String(metricName),
+				Dimensions: dimensions,
+			},
+			Period: aws.Int64(int64(window.Seconds())),
+			Stat:   aws.String("Average"),
+		},
+		ReturnData: aws.Bool(true),
+	}
+}
+
+// CalculateRequiredClusterSize returns the largest cluster size required by the CPU utilization
+// and any of the enabled scaling metrics
+func (m *Metrics) CalculateRequiredClusterSize(status *types.ClusterStatus, minReaders uint) uint {
+	var numberOfServers uint
+	switch {
+	case status.CurrentActiveReaders == 0:
+		numberOfServers = m.calculateWriterLoadClusterSize(status, minReaders)
+	case m.isCapacityWeighted(status):
+		numberOfServers = m.CalculateCapacityClusterSize(status, minReaders)
+	default:
+		numberOfServers = m.CalculateOptimalClusterSize(status.AverageCPUUtilization, status.CurrentActiveReaders, minReaders)
+	}
+
+	// the instances the load is spread over, the writer while it serves the reads alone
+	loadInstances := max(1, status.CurrentActiveReaders)
+
+	for metricName, target := range m.ScalingTargets() {
+		value, ok := status.Metrics[metricName]
+		if !ok {
+			continue
+		}
+
+		var required uint
+		if metricName == MetricAuroraReplicaLag {
+			// add one reader at a time while the lag is above the target, which also blocks scale in
+			if value <= target {
+				continue
+			}
+			required = status.CurrentActiveReaders + 1
+		} else {
+			required = uint(math.Ceil(value * float64(loadInstances) / target))
+		}
+		numberOfServers = max(numberOfServers, min(required, m.config.MaxInstances))
+	}
+
+	return numberOfServers
+}
+
+// calculateWriterLoadClusterSize sizes the readers of a cluster without an available reader, whose
+// reads are served by the writer. The status carries the writer's load, which moves to the readers.
+func (m *Metrics) calculateWriterLoadClusterSize(status *types.ClusterStatus, minReaders uint) uint {
+	numberOfServers := uint(math.Ceil(status.AverageCPUUtilization / m.config.TargetCpuUtil))
+	if readerVCPUs := m.getReaderVCPUs(); status.TotalVCPUs > 0 && readerVCPUs > 0 {
+		neededVCPUs := status.AverageCPUUtilization * status.TotalVCPUs / m.config.TargetCpuUtil
+		numberOfServers = uint(math.Ceil(neededVCPUs / readerVCPUs))
+	}
+	return max(minReaders, min(numberOfServers, m.config.MaxInstances))
+}
+
+// ExceedsTargets reports whether the cluster would be above the scale out target of the CPU
+// utilization or any scaling metric with the given number of readers. A replica lag above the
+// target exceeds it unless readers are added.
+func (m *Metrics) ExceedsTargets(status *types.ClusterStatus, newReaderCount uint) bool {
+	if m.projectedCPUUtilization(status, newReaderCount) > m.config.TargetCpuUtil {
+		return true
+	}
+
+	for metricName, target := range m.ScalingTargets() {
+		value, ok := status.Metrics[metricName]
+		if !ok {
+			continue
+		}
+
+		if metricName == MetricAuroraReplicaLag {
+			if value > target && newReaderCount <= status.CurrentActiveReaders {
+				return true
+			}
+		} else if ProjectedUtilization(value, status.CurrentActiveReaders, newReaderCount) > target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,175 @@
+package scaler
+
+import (
+	"math"
+	"predictive-rds-scaler/types"
+	"sort"
+	"sync"
+	"time"
+)
+
+const accuracyWindow = 24 * time.Hour
+
+// accuracyTracker keeps the predictions until the predicted time arrives and scores them against
+// the cluster status observed at that time.
+type accuracyTracker struct {
+	mutex   sync.Mutex
+	pending map[string][]pendingPrediction
+	samples map[string][]accuracySample
+}
+
+type pendingPrediction struct {
+	target time.Time
+	status *types.ClusterStatus
+}
+
+type accuracySample struct {
+	timestamp     time.Time
+	absoluteError float64
+	percentError  float64
+	hasPercent    bool
+	under         bool
+	over          bool
+}
+
+func newAccuracyTracker() *accuracyTracker {
+	return &accuracyTracker{
+		pending: make(map[string][]pendingPrediction),
+		samples: make(map[string][]accuracySample),
+	}
+}
+
+// record stores a prediction made by the named predictor for the given target time
+func (t *accuracyTracker) record(predictor string, target time.Time, prediction *types.ClusterStatus) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	// a predictor may be consulted twice per tick, e.g. as trend signal and as ensemble member
+	pending := t.pending[predictor]
+	if len(pending) > 0 && pending[len(pending)-1].target.Equal(target) {
+		return
+	}
+
+	t.pending[predictor] = append(pending, pendingPrediction{target: target, status: prediction})
+	if _, ok := t.samples[predictor]; !ok {
+		t.samples[predictor] = nil
+	}
+}
+
+// evaluate scores all predictions that are due with the actual cluster status. Predictions that
+// are overdue by more than a few ticks (e.g. because the cluster status couldn't be determined)
+// are dropped, they would be compared against the wrong point in time.
+func (t *accuracyTracker) evaluate(actual *types.ClusterStatus) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for predictor, pending := range t.pending {
+		remaining := pending[:0]
+		for _, prediction := range pending {
+			if prediction.target.After(actual.Timestamp) {
+				remaining = append(remaining, prediction)
+				continue
+			}
+
+			if actual.Timestamp.Sub(prediction.target) <= 3*tickInterval {
+				t.samples[predictor] = append(t.samples[predictor], newAccuracySample(prediction.status, actual))
+			}
+		}
+		t.pending[predictor] = remaining
+		t.samples[predictor] = trimAccuracySamples(t.samples[predictor], actual.Timestamp.Add(-1*accuracyWindow))
+	}
+}
+
+func newAccuracySample(prediction *types.ClusterStatus, actual *types.ClusterStatus) accuracySample {
+	// the prediction may assume a different number of readers, so compare the utilization the
+	// predicted load would have caused on the readers that were actually active
+	predictedUtilization := prediction.AverageCPUUtilization * float64(prediction.CurrentActiveReaders)
+	if actual.CurrentActiveReaders > 0 {
+		predictedUtilization /= float64(actual.CurrentActiveReaders)
+	}
+
+	sample := accuracySample{
+		timestamp:     actual.Timestamp,
+		absoluteError: math.Abs(predictedUtilization - actual.AverageCPUUtilization),
+		under:         prediction.OptimalSize < actual.OptimalSize,
+		over:          prediction.OptimalSize > actual.OptimalSize,
+	}
+
+	if actual.AverageCPUUtilization > 0 {
+		sample.percentError = 100 * sample.absoluteError / actual.AverageCPUUtilization
+		sample.hasPercent = true
+	}
+
+	return sample
+}
+
+func trimAccuracySamples(samples []accuracySample, since time.Time) []accuracySample {
+	first := sort.Search(len(samples), func(i int) bool {
+		return !samples[i].timestamp.Before(since)
+	})
+	return samples[first:]
+}
+
+// report summarizes the samples of every predictor within the trailing window
+func (t *accuracyTracker) report(now time.Time, window time.Duration) []*types.PredictionAccuracy {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	predictors := make([]string, 0, len(t.samples))
+	for predictor := range t.samples {
+		predictors = append(predictors, predictor)
+	}
+	sort.Strings(predictors)
+
+	report := make([]*types.PredictionAccuracy, 0, len(predictors))
+	for _, predictor := range predictors {
+		report = append(report, summarizeAccuracy(predictor, t.samples[predictor], now.Add(-1*window), window))
+	}
+	return report
+}
+
+// meanAbsoluteError returns the MAE of the named predictor within the trailing window and the
+// number of samples it is based on
+func (t *accuracyTracker) meanAbsoluteError(predictor string, now time.Time, window time.Duration) (float64, uint) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	accuracy := summarizeAccuracy(predictor, t.samples[predictor], now.Add(-1*window), window)
+	return accuracy.MeanAbsoluteError, accuracy.Samples
+}
+
+func summarizeAccuracy(predictor string, samples []accuracySample, since time.Time, window time.Duration) *types.PredictionAccuracy {
+	accuracy := &types.PredictionAccuracy{
+		Predictor: predictor,
+		Window:    window,
+	}
+
+	var absoluteErrorSum, percentErrorSum float64
+	var percentSamples uint
+	for _, sample := range trimAccuracySamples(samples, since) {
+		accuracy.Samples++
+		absoluteErrorSum += sample.absoluteError
+
+		if sample.hasPercent {
+			percentErrorSum += sample.percentError
+			percentSamples++
+		}
+
+		// every sample stands for one tick of the scaler
+		if sample.under {
+			accuracy.UnderProvisionedMinutes += tickInterval.Minutes()
+		}
+		if sample.over {
+			accuracy.OverProvisionedMinutes += tickInterval.Minutes()
+		}
+	}
+
+	if accuracy.Samples > 0 {
+		accuracy.MeanAbsoluteError = absoluteErrorSum / float64(accuracy.Samples)
+	}
+	if percentSamples > 0 {
+		accuracy.MeanAbsolutePercentError = percentErrorSum / float64(percentSamples)
+	}
+
+	return accuracy
+}
@@ -0,0 +1,84 @@
+package scaler
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSummarizeAccuracy(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	window := time.Hour
+
+	tests := []struct {
+		name              string
+		samples           []accuracySample
+		expectedSamples   uint
+		expectedMAE       float64
+		expectedMAPE      float64
+		expectedUnderMins float64
+		expectedOverMins  float64
+	}{
+		{
+			name: "no samples",
+		},
+		{
+			name: "mean of absolute and percent errors",
+			samples: []accuracySample{
+				{timestamp: now.Add(-30 * time.Minute), absoluteError: 2, percentError: 10, hasPercent: true},
+				{timestamp: now.Add(-20 * time.Minute), absoluteError: 4, percentError: 20, hasPercent: true, under: true},
+				{timestamp: now.Add(-10 * time.Minute), absoluteError: 6, percentError: 60, hasPercent: true, over: true},
+			},
+			expectedSamples:   3,
+			expectedMAE:       4,
+			expectedMAPE:      30,
+			expectedUnderMins: tickInterval.Minutes(),
+			expectedOverMins:  tickInterval.Minutes(),
+		},
+		{
+			name: "samples without actual load are left out of the percent error",
+			samples: []accuracySample{
+				{timestamp: now.Add(-20 * time.Minute), absoluteError: 3, percentError: 15, hasPercent: true},
+				{timestamp: now.Add(-10 * time.Minute), absoluteError: 5},
+			},
+			expectedSamples: 2,
+			expectedMAE:     4,
+			expectedMAPE:    15,
+		},
+		{
+			name: "samples before the window are left out",
+			samples: []accuracySample{
+				{timestamp: now.Add(-2 * time.Hour), absoluteError: 100, percentError: 100, hasPercent: true, under: true},
+				{timestamp: now.Add(-10 * time.Minute), absoluteError: 1, percentError: 5, hasPercent: true},
+			},
+			expectedSamples: 1,
+			expectedMAE:     1,
+			expectedMAPE:    5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accuracy := summarizeAccuracy(PredictorWeekAgo, tt.samples, now.Add(-1*window), window)
+
+			if accuracy.Predictor != PredictorWeekAgo || accuracy.Window != window {
+				t.Errorf("got predictor %s and window %s", accuracy.Predictor, accuracy.Window)
+			}
+			if accuracy.Samples != tt.expectedSamples {
+				t.Errorf("Samples = %d, expected %d", accuracy.Samples, tt.expectedSamples)
+			}
+			if math.Abs(accuracy.MeanAbsoluteError-tt.expectedMAE) > 1e-9 {
+				t.Errorf("MeanAbsoluteError = %f, expected %f", accuracy.MeanAbsoluteError, tt.expectedMAE)
+			}
+			if math.Abs(accuracy.MeanAbsolutePercentError-tt.expectedMAPE) > 1e-9 {
+				t.Errorf("MeanAbsolutePercentError = %f, expected %f", accuracy.MeanAbsolutePercentError, tt.expectedMAPE)
+			}
+			if accuracy.UnderProvisionedMinutes != tt.expectedUnderMins {
+				t.Errorf("UnderProvisionedMinutes = %f, expected %f", accuracy.UnderProvisionedMinutes, tt.expectedUnderMins)
+			}
+			if accuracy.OverProvisionedMinutes != tt.expectedOverMins {
+				t.Errorf("OverProvisionedMinutes = %f, expected %f", accuracy.OverProvisionedMinutes, tt.expectedOverMins)
+			}
+		})
+	}
+}
@@ -10,16 +10,56 @@ import (
 	"predictive-rds-scaler/metrics"
 	"predictive-rds-scaler/types"
 	"strconv"
+	"sync"
 	"time"
 )
 
+const tickInterval = 10 * time.Second
+
 type Scaler struct {
 	config       *types.Config
 	scalerStatus types.Cooldown
 	rdsClient    *rds.RDS
 	logger       *zerolog.Logger
 	broadcast    chan types.Broadcast
+	stop         chan struct{}
 	metrics      *metrics.Metrics
+	predictor    Predictor
+	trend        Predictor
+	accuracy     *accuracyTracker
+	calendar     *Calendar
+	schedule     []*ScheduleRule
+	location     *time.Location
+
+	// instance classes of new readers in order of preference, empty to clone the writer's class
+	readerInstanceClasses []string
+	// instance classes managed readers are moved along when capped on the number of readers
+	verticalScalingClasses []string
+	// maximum capacity of the Serverless v2 instances in ACUs
+	serverlessMaxCapacity float64
+	// custom endpoints new readers are added to
+	customEndpoints []string
+	// AZs readers are balanced across, empty to use the AZs of the cluster
+	availabilityZones []string
+	// placements that ran out of capacity and the time until they are skipped
+	unavailablePlacements map[placement]time.Time
+	// readers with the name prefix but without the ownership tag that were already logged
+	untaggedInstances map[string]bool
+
+	recommendations      []sizeRecommendation
+	recommendationsSince time.Time
+
+	// serverless minimum capacities required within the stabilization window
+	capacityRecommendations      []capacityRecommendation
+	capacityRecommendationsSince time.Time
+
+	// actions planned in dry-run mode in the current and the previous scaling run
+	plannedActions         []types.PlannedAction
+	previousPlannedActions []types.PlannedAction
+
+	// latest cluster status, read by the API
+	clusterStatusMutex sync.RWMutex
+	clusterStatus      *types.ClusterStatus
 }
 
 func New(conf *types.Config, logger *zerolog.Logger, awsSession *session.Session, broadcast chan types.Broadcast) (*Scaler, error) {
@@ -29,26 +69,129 @@ func New(conf *types.Config, logger *zerolog.Logger, awsSession *session.Session
 
 	cloudwatchMetrics := metrics.New(*conf, logger, awsSession)
 
+	// 0 scales in at the target
+	if conf.ScaleInCpuUtil > 0 && conf.ScaleInCpuUtil >= conf.TargetCpuUtil {
+		return nil, fmt.Errorf("scale in CPU utilization (%.1f) must be below the target CPU utilization (%.1f)", conf.ScaleInCpuUtil, conf.TargetCpuUtil)
+	}
+
+	if conf.PromotionTier < 0 || conf.PromotionTier > 15 {
+		return nil, fmt.Errorf("promotion tier must be between 0 and 15, got %d", conf.PromotionTier)
+	}
+
+	switch conf.FailoverAction {
+	case FailoverActionNone, FailoverActionFailback, FailoverActionAdopt:
+	default:
+		return nil, fmt.Errorf("unknown failover action: %s", conf.FailoverAction)
+	}
+
+	switch conf.ScalingMode {
+	case ScalingModeInstances, "":
+	case ScalingModeServerless:
+		if conf.ServerlessMinCapacity < serverlessCapacityStep {
+			return nil, fmt.Errorf("serverless minimum capacity must be at least %.1f ACUs", serverlessCapacityStep)
+		}
+	case ScalingModeMixed:
+		if conf.BurstDuration <= 0 {
+			return nil, fmt.Errorf("mixed scaling mode requires a burst duration")
+		}
+	default:
+		return nil, fmt.Errorf("unknown scaling mode: %s", conf.ScalingMode)
+	}
+
+	location, err := time.LoadLocation(conf.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone: %v", err)
+	}
+
+	verticalScalingClasses, err := parseVerticalScalingClasses(conf.VerticalScalingClasses)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vertical scaling classes: %v", err)
+	}
+
+	readerInstanceClasses := parseReaderInstanceClasses(conf.ReaderInstanceClass, conf.ReaderInstanceClassFallbacks)
+	for _, instanceClass := range readerInstanceClasses {
+		if _, err := metrics.InstanceClassVCPUs(instanceClass); err != nil {
+			return nil, fmt.Errorf("invalid reader instance class: %v", err)
+		}
+	}
+
+	calendar := newCalendar(location)
+	if conf.CalendarFile != "" {
+		calendar, err = LoadCalendar(conf.CalendarFile, location)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load calendar: %v", err)
+		}
+	}
+
+	boostHours, err := parseBoostHours(conf.BoostHours)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing boost hours: %v", err)
+	}
+
+	schedule, err := boostHourRules(boostHours, conf.MinInstances)
+	if err != nil {
+		return nil, err
+	}
+
+	if conf.ScheduleFile != "" {
+		rules, err := LoadSchedule(conf.ScheduleFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load schedule: %v", err)
+		}
+		schedule = append(schedule, rules...)
+	}
+
+	accuracy := newAccuracyTracker()
+
+	predictor, err := newPredictor(conf, cloudwatchMetrics, calendar, accuracy)
+	if err != nil {
+		return nil, err
+	}
+
+	// the short-term trend is an additional signal next to the prediction, disabled without a window
+	var trend Predictor
+	if conf.TrendWindow > 0 {
+		trend, err = newTrendPredictor(conf, cloudwatchMetrics)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &Scaler{
 		config:       conf,
-		scalerStatus: types.Cooldown{Threshold: 0},
+		scalerStatus: types.Cooldown{},
 		rdsClient:    rdsClient,
 		metrics:      cloudwatchMetrics,
+		predictor:    predictor,
+		trend:        trend,
+		accuracy:     accuracy,
+		calendar:     calendar,
+		schedule:     schedule,
+		location:     location,
 		logger:       logger,
 		broadcast:    broadcast,
+		stop:         make(chan struct{}),
+
+		readerInstanceClasses:  readerInstanceClasses,
+		verticalScalingClasses: verticalScalingClasses,
+		customEndpoints:        parseList(conf.CustomEndpoints),
+		availabilityZones:      parseList(conf.AvailabilityZones),
+		unavailablePlacements:  make(map[placement]time.Time),
+		untaggedInstances:      make(map[string]bool),
 	}, nil
 }
 
 func (s *Scaler) Run() {
-	ticker := time.NewTicker(10 * time.Second)
-
-	boostHours, err := parseBoostHours(s.config.BoostHours)
-	if err != nil {
-		s.logger.Error().Err(err).Msg("Error parsing scale out hours")
-	}
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
 
-	for range ticker.C {
-		s.scale(boostHours)
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.scale()
+		}
 	}
 }
 
@@ -56,7 +199,9 @@ func (s *Scaler) Shutdown() {
 	close(s.broadcast)
 }
 
-func (s *Scaler) scale(boostHours []int) {
+func (s *Scaler) scale() {
+	s.startPlanning()
+
 	// determine current status
 	clusterStatus, err := s.getClusterStatus()
 	if err != nil {
@@ -70,33 +215,76 @@ func (s *Scaler) scale(boostHours []int) {
 		Uint("OptimalSize", clusterStatus.OptimalSize).
 		Msg("Cluster status")
 
+	s.clusterStatusMutex.Lock()
+	s.clusterStatus = clusterStatus
+	s.clusterStatusMutex.Unlock()
+
 	// broadcast current status for UI
 	s.submitBroadcast(&types.Broadcast{MessageType: "clusterStatus", Data: clusterStatus})
 
-	// receive historical data
-	historicStatus, err := s.metrics.GetHistoricClusterStatus(s.config.PlanAheadTime)
+	// a managed reader promoted by a failover can't be scaled in
+	s.checkManagedWriter(clusterStatus)
+
+	// score the predictions made for this point in time
+	s.accuracy.evaluate(clusterStatus)
+	s.submitBroadcast(&types.Broadcast{MessageType: "predictionAccuracy", Data: s.GetPredictionAccuracy()})
+
+	// predict the status PlanAheadTime from now
+	now := time.Now().In(time.UTC)
+	predictedStatus, err := s.predictor.Predict(now, s.config.PlanAheadTime)
 	if err != nil {
-		s.logger.Error().Err(err).Msg("Error getting historic cluster status")
+		s.logger.Error().Err(err).Str("Predictor", s.predictor.Name()).Msg("Error predicting cluster status")
 		return
 	}
+	s.accuracy.record(s.predictor.Name(), now.Add(s.config.PlanAheadTime), predictedStatus)
+	s.estimateCapacity(predictedStatus, clusterStatus)
 
 	s.logger.Info().
-		Str("AverageCPUUtilization", strconv.FormatFloat(historicStatus.AverageCPUUtilization, 'f', 2, 64)).
-		Uint("CurrentActiveReaders", historicStatus.CurrentActiveReaders).
-		Uint("OptimalSize", historicStatus.OptimalSize).
-		Msg("Historic status")
+		Str("Predictor", s.predictor.Name()).
+		Str("AverageCPUUtilization", strconv.FormatFloat(predictedStatus.AverageCPUUtilization, 'f', 2, 64)).
+		Uint("CurrentActiveReaders", predictedStatus.CurrentActiveReaders).
+		Uint("OptimalSize", predictedStatus.OptimalSize).
+		Msg("Predicted status")
 
-	s.submitBroadcast(&types.Broadcast{MessageType: "clusterStatusPrediction", Data: historicStatus})
+	s.submitBroadcast(&types.Broadcast{MessageType: "clusterStatusPrediction", Data: predictedStatus})
 
-	minInstances := s.config.MinInstances
-	if isBoostHour(time.Now().In(time.UTC).Hour(), boostHours) {
-		minInstances = s.config.MinInstances + 1
+	maxOptimalSize := math.Max(float64(clusterStatus.OptimalSize), float64(predictedStatus.OptimalSize))
+	forecasts := []*types.ClusterStatus{predictedStatus}
+
+	// extrapolate the short-term trend, a failure here must not block the scaling decision
+	if s.trend != nil {
+		trendStatus, err := s.trend.Predict(now, s.config.PlanAheadTime)
+		if err != nil {
+			s.logger.Error().Err(err).Msg("Error extrapolating cluster status trend")
+		} else {
+			s.accuracy.record(s.trend.Name(), now.Add(s.config.PlanAheadTime), trendStatus)
+			s.estimateCapacity(trendStatus, clusterStatus)
+			s.logger.Info().
+				Str("AverageCPUUtilization", strconv.FormatFloat(trendStatus.AverageCPUUtilization, 'f', 2, 64)).
+				Uint("CurrentActiveReaders", trendStatus.CurrentActiveReaders).
+				Uint("OptimalSize", trendStatus.OptimalSize).
+				Msg("Trend status")
+
+			s.submitBroadcast(&types.Broadcast{MessageType: "clusterStatusTrend", Data: trendStatus})
+			maxOptimalSize = math.Max(maxOptimalSize, float64(trendStatus.OptimalSize))
+			forecasts = append(forecasts, trendStatus)
+		}
 	}
-	maxOptimalSize := math.Max(float64(clusterStatus.OptimalSize), float64(historicStatus.OptimalSize))
+
+	// Serverless v2 instances scale themselves, only their floor is raised ahead of the load
+	if s.config.ScalingMode == ScalingModeServerless {
+		s.scaleServerlessCapacity(now, clusterStatus, forecasts...)
+		return
+	}
+
+	minInstances, maxInstances := s.instanceLimits(now)
 	maxWithMinInstances := math.Max(float64(minInstances), maxOptimalSize)
-	predictedOptimalSizeFloat := math.Min(float64(s.config.MaxInstances), maxWithMinInstances)
+	predictedOptimalSizeFloat := math.Min(float64(maxInstances), maxWithMinInstances)
 	predictedOptimalSize := uint(predictedOptimalSizeFloat)
 
+	s.recordRecommendation(now, predictedOptimalSize)
+	s.submitBroadcast(&types.Broadcast{MessageType: "scalerStatus", Data: s.scalerStatus})
+
 	if predictedOptimalSize == clusterStatus.CurrentActiveReaders {
 		s.logger.Info().
 			Uint("Actual", clusterStatus.CurrentActiveReaders).
@@ -104,6 +292,12 @@ func (s *Scaler) scale(boostHours []int) {
 			Msg("Cluster size is optimal")
 	}
 
+	// capped on the number of readers, the managed readers themselves have to grow
+	if len(s.verticalScalingClasses) > 0 && predictedOptimalSize >= maxInstances && clusterStatus.CurrentActiveReaders >= maxInstances {
+		s.scaleUp(now, clusterStatus, predictedStatus)
+		return
+	}
+
 	if predictedOptimalSize > clusterStatus.CurrentActiveReaders {
 		s.logger.Info().
 			Uint("Actual", clusterStatus.CurrentActiveReaders).
@@ -115,8 +309,15 @@ func (s *Scaler) scale(boostHours []int) {
 			return
 		}
 
-		err := s.scaleOut(s.config.InstanceNamePrefix, predictedOptimalSize-clusterStatus.CurrentActiveReaders)
+		if s.isScaleOutCoolingDown(now) {
+			s.logger.Info().Time("Timeout", s.scalerStatus.ScaleOutTimeout).Msg("Skipping scale out: Cooldown in progress")
+			return
+		}
+
+		plans := s.readerPlans(now, clusterStatus, predictedOptimalSize-clusterStatus.CurrentActiveReaders, minInstances, maxInstances)
+		err := s.scaleOut(s.config.InstanceNamePrefix, plans, maxInstances)
 		if err != nil {
+			s.logger.Error().Err(err).Msg("Error scaling out")
 			return
 		}
 	}
@@ -132,13 +333,52 @@ func (s *Scaler) scale(boostHours []int) {
 			return
 		}
 
-		err := s.scaleIn(clusterStatus.CurrentActiveReaders - predictedOptimalSize)
+		if s.isScaleInCoolingDown(now) {
+			s.logger.Info().Time("Timeout", s.scalerStatus.ScaleInTimeout).Msg("Skipping scale in: Cooldown in progress")
+			return
+		}
+
+		// only scale in as far as the desired size stayed lower for the whole stabilization window
+		stabilizedSize, ok := s.stabilizedScaleInSize(clusterStatus.CurrentActiveReaders)
+		if !ok {
+			s.logger.Info().
+				Uint("Threshold", s.scalerStatus.Threshold).
+				Dur("StabilizationWindow", s.config.ScaleInStabilizationWindow).
+				Msg("Skipping scale in: Optimal size not stable within the stabilization window")
+			return
+		}
+
+		// shrink scaled up readers before removing any
+		if len(s.verticalScalingClasses) > 0 && s.scaleDown(clusterStatus, predictedStatus) {
+			return
+		}
+
+		// never remove so many readers that the remaining ones would have to scale out again
+		for stabilizedSize < clusterStatus.CurrentActiveReaders &&
+			(s.metrics.ExceedsTargets(clusterStatus, stabilizedSize) || s.metrics.ExceedsTargets(predictedStatus, stabilizedSize)) {
+			stabilizedSize++
+		}
+
+		if stabilizedSize >= clusterStatus.CurrentActiveReaders {
+			s.logger.Info().Msg("Skipping scale in: Projected utilization would exceed the scale out target")
+			return
+		}
+
+		preferServerless := s.preferServerlessScaleIn(now, clusterStatus, minInstances, maxInstances)
+		err := s.scaleIn(clusterStatus.CurrentActiveReaders-stabilizedSize, preferServerless, clusterStatus, predictedStatus)
 		if err != nil {
 			s.logger.Error().Err(err).Msg("Error scaling in")
 		}
 	}
 }
 
+// GetClusterStatus returns the latest cluster status, nil before the first scaling run
+func (s *Scaler) GetClusterStatus() *types.ClusterStatus {
+	s.clusterStatusMutex.RLock()
+	defer s.clusterStatusMutex.RUnlock()
+	return s.clusterStatus
+}
+
 func (s *Scaler) GetClusterStatusHistory(duration time.Duration) []*types.ClusterStatus {
 	start := time.Now().In(time.UTC).Add(-1 * duration)
 	statusHistory, err := s.metrics.GetClusterStatus(start, time.Now().In(time.UTC), 5*time.Minute)
@@ -150,7 +390,7 @@ func (s *Scaler) GetClusterStatusHistory(duration time.Duration) []*types.Cluste
 }
 
 func (s *Scaler) GetClusterStatusPredictionHistory(duration time.Duration) []*types.ClusterStatus {
-	end := time.Now().In(time.UTC).Add(-7 * 24 * time.Hour).Add(s.config.PlanAheadTime)
+	end := metrics.SameTimeWeeksAgo(time.Now(), 1, s.location).Add(s.config.PlanAheadTime)
 	start := end.Add(-1 * duration)
 	statusPrediction, err := s.metrics.GetClusterStatus(start, end, 5*time.Minute)
 
@@ -160,15 +400,40 @@ func (s *Scaler) GetClusterStatusPredictionHistory(duration time.Duration) []*ty
 	}
 
 	for key, predictedStatus := range statusPrediction {
-		statusPrediction[key].Timestamp = predictedStatus.Timestamp.
-			Add(7 * 24 * time.Hour).         // Add 7 days to the timestamp to get the predicted time
-			Add(-1 * s.config.PlanAheadTime) // Shift time back by the PlanAheadTime
+		statusPrediction[key].Timestamp = metrics.
+			SameTimeWeeksAgo(predictedStatus.Timestamp, -1, s.location). // Add a week to the timestamp to get the predicted time
+			Add(-1 * s.config.PlanAheadTime)                             // Shift time back by the PlanAheadTime
 	}
 	return statusPrediction
 }
 
+// estimateCapacity sizes a predicted status in vCPUs, assuming its readers have the average
+// capacity of the current ones, as the history doesn't record the instance classes
+func (s *Scaler) estimateCapacity(predictedStatus *types.ClusterStatus, clusterStatus *types.ClusterStatus) {
+	// without readers the capacity is the writer's, which says nothing about the readers
+	if clusterStatus.TotalVCPUs == 0 || clusterStatus.CurrentActiveReaders == 0 || predictedStatus.TotalVCPUs > 0 {
+		return
+	}
+
+	vcpusPerReader := clusterStatus.TotalVCPUs / float64(clusterStatus.CurrentActiveReaders)
+	predictedStatus.TotalVCPUs = vcpusPerReader * float64(predictedStatus.CurrentActiveReaders)
+	predictedStatus.OptimalSize = s.metrics.CalculateRequiredClusterSize(predictedStatus, s.config.MinInstances)
+}
+
+// instanceLimits returns the instance limits in effect at the given time
+func (s *Scaler) instanceLimits(now time.Time) (uint, uint) {
+	minInstances, maxInstances := scheduledInstanceLimits(s.schedule, now, s.config.MinInstances, s.config.MaxInstances)
+
+	// an explicit override on the calendar wins over the regular limits
+	return s.calendar.instanceLimits(now, minInstances, maxInstances)
+}
+
+// GetPredictionAccuracy scores the predictions of the last day against the actual cluster status
+func (s *Scaler) GetPredictionAccuracy() []*types.PredictionAccuracy {
+	return s.accuracy.report(time.Now().In(time.UTC), accuracyWindow)
+}
+
 func (s *Scaler) getClusterStatus() (*types.ClusterStatus, error) {
-	var totalCPUUtilization float64
 	var clusterStatus = types.ClusterStatus{
 		Identifier: s.config.RdsClusterName,
 		Timestamp:  time.Now().In(time.UTC),
@@ -185,60 +450,100 @@ func (s *Scaler) getClusterStatus() (*types.ClusterStatus, error) {
 		return nil, fmt.Errorf("didn't get reader instances: %v", err)
 	}
 
-	// Collect information about the writer readerInstance
-	writerUtilization, err := s.metrics.GetCurrentInstanceUtilization(writerInstance)
-	if err != nil {
-		return nil, fmt.Errorf("didn't get current CPU utilization: %v", err)
+	if s.config.ScalingMode == ScalingModeServerless || s.config.ScalingMode == ScalingModeMixed {
+		scalingConfiguration, err := s.getServerlessScalingConfiguration()
+		if err != nil {
+			return nil, fmt.Errorf("didn't get serverless scaling configuration: %v", err)
+		}
+		s.serverlessMaxCapacity = aws.Float64Value(scalingConfiguration.MaxCapacity)
 	}
 
-	writerStatus := types.InstanceStatus{
-		Identifier:     *writerInstance.DBInstanceIdentifier,
-		IsWriter:       true,
-		Status:         *writerInstance.DBInstanceStatus,
-		CPUUtilization: writerUtilization,
+	// the cluster is sized in units of the preferred reader instance class
+	readerVCPUs, err := s.instanceVCPUs(s.readerInstanceClassesFor(writerInstance)[0])
+	if err != nil {
+		s.logger.Warn().Err(err).Msg("Capacity of new readers unknown, sizing the cluster by instance count")
 	}
+	s.metrics.SetReaderVCPUs(readerVCPUs)
 
-	s.logInstanceStatus(writerStatus)
+	// Collect information about the writer readerInstance
+	writerStatus, err := s.getInstanceStatus(writerInstance, true)
+	if err != nil {
+		return nil, err
+	}
 
-	totalCPUUtilization += writerStatus.CPUUtilization
-	clusterStatus.CurrentActiveReaders = 1
+	var activeInstances []types.InstanceStatus
+	clusterStatus.WriterCPUUtilization = writerStatus.CPUUtilization
 	clusterStatus.Instances = append(clusterStatus.Instances, writerStatus)
+	if !s.config.ExcludeWriter {
+		activeInstances = append(activeInstances, writerStatus)
+	}
 
 	// Collect information about reader instances
 	for _, readerInstance := range readerInstances {
-		readerUtilization, err := s.metrics.GetCurrentInstanceUtilization(readerInstance)
+		readerStatus, err := s.getInstanceStatus(readerInstance, false)
 		if err != nil {
 			return nil, err
 		}
 
-		readerStatus := types.InstanceStatus{
-			Identifier:     *readerInstance.DBInstanceIdentifier,
-			IsWriter:       false,
-			Status:         *readerInstance.DBInstanceStatus,
-			CPUUtilization: readerUtilization,
-		}
-
-		s.logInstanceStatus(readerStatus)
-
 		if readerStatus.Status == "available" {
-			clusterStatus.CurrentActiveReaders++
-			totalCPUUtilization += readerStatus.CPUUtilization
+			activeInstances = append(activeInstances, readerStatus)
 		}
 		clusterStatus.Instances = append(clusterStatus.Instances, readerStatus)
 	}
 
-	clusterStatus.AverageCPUUtilization = totalCPUUtilization / float64(clusterStatus.CurrentActiveReaders)
-	clusterStatus.OptimalSize = s.metrics.CalculateOptimalClusterSize(clusterStatus.AverageCPUUtilization, clusterStatus.CurrentActiveReaders, s.config.MinInstances)
+	loadInstances := activeInstances
+	if len(activeInstances) == 0 {
+		// without an available reader the reader endpoint is served by the writer, its load is the
+		// load the readers have to take over, but it doesn't count as one of them
+		loadInstances = []types.InstanceStatus{writerStatus}
+	}
+
+	clusterStatus.CurrentActiveReaders = uint(len(activeInstances))
+	clusterStatus.AverageCPUUtilization, clusterStatus.TotalVCPUs = averageInstanceUtilization(loadInstances)
+	clusterStatus.Metrics = averageInstanceMetrics(loadInstances)
+	clusterStatus.OptimalSize = s.metrics.CalculateRequiredClusterSize(&clusterStatus, s.config.MinInstances)
 
 	return &clusterStatus, nil
 }
 
+func (s *Scaler) getInstanceStatus(instance *rds.DBInstance, isWriter bool) (types.InstanceStatus, error) {
+	utilization, err := s.metrics.GetCurrentInstanceUtilization(instance)
+	if err != nil {
+		return types.InstanceStatus{}, fmt.Errorf("didn't get current CPU utilization: %v", err)
+	}
+
+	instanceMetrics, err := s.metrics.GetCurrentInstanceMetrics(instance)
+	if err != nil {
+		return types.InstanceStatus{}, fmt.Errorf("didn't get current scaling metrics: %v", err)
+	}
+
+	// an unknown class leaves the vCPUs at 0, which falls back to sizing by instance count
+	vcpus, _ := s.instanceVCPUs(aws.StringValue(instance.DBInstanceClass))
+
+	instanceStatus := types.InstanceStatus{
+		Identifier:     *instance.DBInstanceIdentifier,
+		IsWriter:       isWriter,
+		IsManaged:      s.isManagedInstance(instance),
+		Status:         *instance.DBInstanceStatus,
+		InstanceClass:  aws.StringValue(instance.DBInstanceClass),
+		VCPUs:          vcpus,
+		CPUUtilization: utilization,
+		Metrics:        instanceMetrics,
+	}
+
+	s.logInstanceStatus(instanceStatus)
+
+	return instanceStatus, nil
+}
+
 func (s *Scaler) logInstanceStatus(writerStatus types.InstanceStatus) {
 	s.logger.Info().
 		Str("Identifier", writerStatus.Identifier).
 		Bool("IsWriter", writerStatus.IsWriter).
 		Str("Status", writerStatus.Status).
+		Str("InstanceClass", writerStatus.InstanceClass).
 		Float64("CPUUtilization", writerStatus.CPUUtilization).
+		Interface("Metrics", writerStatus.Metrics).
 		Msg("Instance status")
 }
 
@@ -252,45 +557,39 @@ func (s *Scaler) logScalerStatus(cpuUtilization float64, currentSize int) {
 
 func (s *Scaler) submitBroadcast(broadcast *types.Broadcast) {
 	if broadcast.Data != nil {
+		broadcast.Cluster = s.config.RdsClusterName
 		go func() {
 			s.broadcast <- *broadcast
 		}()
 	}
 }
 
-func (s *Scaler) scaleOut(readerNamePrefix string, numInstances uint) error {
-	s.scalerStatus.IsScaling = true
-
-	currentHour := time.Now().In(time.UTC).Hour()
-	newReaderInstanceNames := make([]string, numInstances)
+func (s *Scaler) scaleOut(readerNamePrefix string, plans []readerPlan, maxInstances uint) error {
+	if s.config.DryRun {
+		return s.planScaleOut(plans)
+	}
 
-	for i := 0; i < int(numInstances); i++ {
-		// Get the current writer instance
-		writerInstance, err := s.getWriterInstance()
-		if err != nil {
-			return fmt.Errorf("failed to get current writer instance: %v", err)
-		}
+	s.scalerStatus.IsScaling = true
 
-		readerInstances, err := s.getReaderInstances(StatusAll ^ StatusDeleting)
-		if (len(readerInstances) + 1) >= int(s.config.MaxInstances) {
-			return fmt.Errorf("max number of instances reached")
+	var newReaderInstanceNames []string
+	var err error
+	for _, plan := range plans {
+		if plan.count == 0 {
+			continue
 		}
 
-		// Generate a random UID for the new reader instance name
-		randomUID := generateRandomUID()
-
-		// Create the reader instance name with the prefix, current scale-out hour, and random UID
-		readerName := fmt.Sprintf("%s%d-%s", readerNamePrefix, currentHour, randomUID)
-
-		_, err = s.createReaderInstance(readerName, writerInstance)
+		var names []string
+		names, err = s.createReaderInstances(readerNamePrefix, plan.count, maxInstances, plan.instanceClasses)
+		newReaderInstanceNames = append(newReaderInstanceNames, names...)
 		if err != nil {
-			return fmt.Errorf("failed to add reader instance: %v", err)
+			break
 		}
+	}
 
-		s.logger.Info().Str("NewReaderInstanceName", readerName).Msg("Scaling out operation successful")
-
-		// Add the new reader instance name to the slice
-		newReaderInstanceNames[i] = readerName
+	if len(newReaderInstanceNames) == 0 {
+		// nothing is pending, so the next tick may try again
+		s.scalerStatus.IsScaling = false
+		return err
 	}
 
 	go func() {
@@ -298,18 +597,18 @@ func (s *Scaler) scaleOut(readerNamePrefix string, numInstances uint) error {
 		err := s.waitForInstancesAvailable(newReaderInstanceNames)
 		elapsed := time.Since(start)
 
-		s.scalerStatus.LastScale = time.Now().In(time.UTC)
+		s.startScaleOutCooldown(time.Now().In(time.UTC))
 		s.scalerStatus.IsScaling = false
 
-		if err != nil {
-			s.logger.Error().Err(err).Msg("Error setting cooldown status")
-		}
-
 		if err != nil {
 			s.logger.Error().Err(err).Msg("Error waiting for instances to become 'Available'")
 			return
 		}
 
+		if err := s.addToCustomEndpoints(newReaderInstanceNames); err != nil {
+			s.logger.Error().Err(err).Msg("Error adding readers to custom endpoints")
+		}
+
 		// Adjust PlanAheadTime if elapsed time + buffer is greater
 		if adjustedTime := elapsed + 60*time.Second; adjustedTime > s.config.PlanAheadTime {
 			s.config.PlanAheadTime = adjustedTime
@@ -317,51 +616,205 @@ func (s *Scaler) scaleOut(readerNamePrefix string, numInstances uint) error {
 		}
 	}()
 
-	return nil
+	return err
 }
 
-func (s *Scaler) scaleIn(numInstances uint) error {
-	s.scalerStatus.IsScaling = true
+// createReaderInstances creates readers until the capacity of numInstances readers of the preferred
+// class is added, and returns the names of the readers that were created, even if a later one
+// failed. Readers of a fallback class count by their own capacity. Without instance classes the
+// reader instance classes are used.
+func (s *Scaler) createReaderInstances(readerNamePrefix string, numInstances uint, maxInstances uint, instanceClasses []string) ([]string, error) {
+	currentHour := time.Now().In(time.UTC).Hour()
+	newReaderInstanceNames := make([]string, 0, numInstances)
 
-	readerInstances, err := s.getReaderInstances(StatusAll)
+	// Get the current writer instance
+	writerInstance, err := s.getWriterInstance()
+	if err != nil {
+		return newReaderInstanceNames, fmt.Errorf("failed to get current writer instance: %v", err)
+	}
+
+	if len(instanceClasses) == 0 {
+		instanceClasses = s.readerInstanceClassesFor(writerInstance)
+	}
+	preferredVCPUs, _ := s.instanceVCPUs(instanceClasses[0])
+	requiredVCPUs := preferredVCPUs * float64(numInstances)
+
+	availabilityZones, err := s.availabilityZonesForPlacement()
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Error getting availability zones, leaving the placement to AWS")
+	}
+	placements := make(map[string]string)
+
+	var addedVCPUs float64
+	isComplete := func() bool {
+		// without a known capacity the readers are counted instead
+		if requiredVCPUs > 0 {
+			return addedVCPUs >= requiredVCPUs
+		}
+		return len(newReaderInstanceNames) >= int(numInstances)
+	}
+
+	for !isComplete() {
+		readerInstances, err := s.getReaderInstances(StatusAll ^ StatusDeleting)
+		if err != nil {
+			return newReaderInstanceNames, fmt.Errorf("failed to get reader instances: %v", err)
+		}
+		if (len(readerInstances) + s.writerOffset()) >= int(maxInstances) {
+			return newReaderInstanceNames, fmt.Errorf("max number of instances reached")
+		}
+
+		// Generate a random UID for the new reader instance name
+		randomUID := generateRandomUID()
+
+		// Create the reader instance name with the prefix, current scale-out hour, and random UID
+		readerName := fmt.Sprintf("%s%d-%s", readerNamePrefix, currentHour, randomUID)
+
+		// place the reader in the AZ with the fewest readers, falling back to other AZs and classes
+		candidates := placementCandidates(instanceClasses, availabilityZonesByReaders(availabilityZones, readerInstances, placements))
+
+		created, err := s.createReaderInstanceWithFallback(readerName, writerInstance, candidates)
+		if err != nil {
+			return newReaderInstanceNames, fmt.Errorf("failed to add reader instance: %v", err)
+		}
+		placements[readerName] = created.availabilityZone
+
+		s.logger.Info().
+			Str("NewReaderInstanceName", readerName).
+			Str("InstanceClass", created.instanceClass).
+			Str("AvailabilityZone", created.availabilityZone).
+			Msg("Scaling out operation successful")
+
+		// Add the new reader instance name to the slice
+		newReaderInstanceNames = append(newReaderInstanceNames, readerName)
+
+		vcpus, _ := s.instanceVCPUs(created.instanceClass)
+		addedVCPUs += vcpus
+	}
+
+	return newReaderInstanceNames, nil
+}
+
+// readerInstanceClassesFor returns the instance classes of new readers in order of preference, the
+// writer's class is used unless a reader instance class is configured
+func (s *Scaler) readerInstanceClassesFor(writerInstance *rds.DBInstance) []string {
+	if s.config.ReaderInstanceClass != "" {
+		return s.readerInstanceClasses
+	}
+	return append([]string{aws.StringValue(writerInstance.DBInstanceClass)}, s.readerInstanceClasses...)
+}
+
+// instanceVCPUs returns the capacity of an instance class, Serverless v2 instances count with the
+// maximum capacity of the cluster
+func (s *Scaler) instanceVCPUs(instanceClass string) (float64, error) {
+	return metrics.InstanceCapacityVCPUs(instanceClass, s.serverlessMaxCapacity)
+}
+
+// exceedsTargetsWithout reports whether removing the given vCPUs would push the CPU utilization of
+// any of the statuses above the scale out target, statuses without a known capacity never do
+func (s *Scaler) exceedsTargetsWithout(vcpus float64, statuses ...*types.ClusterStatus) bool {
+	for _, status := range statuses {
+		if status.TotalVCPUs > 0 && vcpus > 0 && s.metrics.ProjectedCapacityUtilization(status, -vcpus) > s.config.TargetCpuUtil {
+			return true
+		}
+	}
+	return false
+}
+
+// writerOffset is the number of instances the writer adds to the cluster size
+func (s *Scaler) writerOffset() int {
+	if s.config.ExcludeWriter {
+		return 0
+	}
+	return 1
+}
+
+// scaleIn removes up to numInstances managed readers. The number is sized in readers of the preferred
+// class, so readers of a larger fallback class are only removed while the remaining capacity keeps
+// the current and predicted load below the scale out target.
+func (s *Scaler) scaleIn(numInstances uint, preferServerless bool, statuses ...*types.ClusterStatus) error {
+	readerInstances, err := s.getReaderInstances(StatusAll ^ StatusDeleting)
 
 	if err != nil {
 		return fmt.Errorf("failed to get reader instances: %v", err)
 	}
 
+	s.warnUntaggedInstances(readerInstances)
+
+	var removedVCPUs float64
+	isRemovable := func(instance *rds.DBInstance) bool {
+		vcpus, _ := s.instanceVCPUs(aws.StringValue(instance.DBInstanceClass))
+		return s.isManagedInstance(instance) && !s.exceedsTargetsWithout(removedVCPUs+vcpus, statuses...)
+	}
+
+	s.scalerStatus.IsScaling = true
+
 	for i := 0; i < int(numInstances); i++ {
-		// Check if there are any reader instances available to scale in
-		if len(readerInstances) == 0 {
+		// Choose a reader instance to remove, keeping the remaining ones balanced across AZs. Only
+		// readers the scaler created are ever deleted, permanent and adopted instances are kept.
+		victim := chooseScaleInVictim(readerInstances, isRemovable, preferServerless)
+		if victim < 0 {
+			if i == 0 {
+				s.scalerStatus.IsScaling = false
+				s.logger.Info().Msg("Skipping scale in: No reader owned by the scaler can be removed")
+			}
 			break
 		}
-
-		// Choose a reader instance to remove
-		instance := readerInstances[i]
+		instance := readerInstances[victim]
+		readerInstances = append(readerInstances[:victim], readerInstances[victim+1:]...)
+
+		vcpus, _ := s.instanceVCPUs(aws.StringValue(instance.DBInstanceClass))
+		removedVCPUs += vcpus
+
+		if s.config.DryRun {
+			s.planAction(types.PlannedAction{
+				Action:             ActionDeleteInstance,
+				InstanceIdentifier: *instance.DBInstanceIdentifier,
+				InstanceClass:      aws.StringValue(instance.DBInstanceClass),
+			})
+			continue
+		}
 
 		// Wait for the instance to become deletable
 		err := s.waitUntilInstanceDeletable(*instance.DBInstanceIdentifier)
 		if err != nil {
+			s.scalerStatus.IsScaling = i > 0
 			return fmt.Errorf("failed to wait for instance to become deletable: %v", err)
 		}
 
+		// Stop routing custom endpoint traffic to the reader before it goes away
+		if err := s.removeFromCustomEndpoints(*instance.DBInstanceIdentifier); err != nil {
+			s.logger.Error().Err(err).Msg("Error removing reader from custom endpoints")
+		}
+
 		// Remove the reader instance
 		_, err = s.rdsClient.DeleteDBInstance(&rds.DeleteDBInstanceInput{
 			DBInstanceIdentifier: instance.DBInstanceIdentifier,
 			SkipFinalSnapshot:    aws.Bool(true),
 		})
 		if err != nil {
+			s.scalerStatus.IsScaling = i > 0
 			return fmt.Errorf("failed to remove reader instance: %v", err)
 		}
 
 		go func() {
 			err := s.waitUntilInstanceIsDeleted(*instance.DBInstanceIdentifier)
+			s.startScaleInCooldown(time.Now().In(time.UTC))
 			s.scalerStatus.IsScaling = false
 			if err != nil {
 				return
 			}
 		}()
 
-		s.logger.Info().Str("InstanceIdentifier", *instance.DBInstanceIdentifier).Str("InstanceStatus", *instance.DBInstanceStatus).Msg("Instance is deleting")
+		s.logger.Info().
+			Str("InstanceIdentifier", *instance.DBInstanceIdentifier).
+			Str("InstanceStatus", *instance.DBInstanceStatus).
+			Str("AvailabilityZone", aws.StringValue(instance.AvailabilityZone)).
+			Msg("Instance is deleting")
+	}
+
+	if s.config.DryRun {
+		s.scalerStatus.IsScaling = false
+		s.startScaleInCooldown(time.Now().In(time.UTC))
 	}
 
 	return nil
@@ -369,5 +822,5 @@ func (s *Scaler) scaleIn(numInstances uint) error {
 
 func (s *Scaler) Stop() {
 	s.logger.Info().Msg("Stopping scaler")
-	close(s.broadcast)
+	close(s.stop)
 }
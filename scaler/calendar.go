@@ -0,0 +1,394 @@
+package scaler
+
+import (
+	"bufio"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"predictive-rds-scaler/metrics"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	CalendarActionIgnore     = "ignore"
+	CalendarActionSubstitute = "substitute"
+	CalendarActionOverride   = "override"
+
+	calendarDateLayout = "2006-01-02"
+
+	// how many weeks a lookup may step back to skip ignored days
+	calendarMaxLookbackWeeks = 4
+
+	// recurring events without an end are expanded up to this many years after the current year
+	calendarRecurrenceYears = 5
+)
+
+// CalendarDay marks a special day. The history of ignored days is left out of predictions, the
+// history of substituted days is replaced by the one of the substitute date, and override days
+// replace the instance limits while they last.
+type CalendarDay struct {
+	Date         string `yaml:"date"`
+	Name         string `yaml:"name"`
+	Action       string `yaml:"action"`
+	Substitute   string `yaml:"substitute"`
+	MinInstances *uint  `yaml:"min_instances"`
+	MaxInstances *uint  `yaml:"max_instances"`
+
+	substitute time.Time
+}
+
+// Calendar holds the special days by date. Dates and the seasonal lookups are based on the
+// wall-clock time of the business time zone.
+type Calendar struct {
+	days     map[string]*CalendarDay
+	location *time.Location
+}
+
+func newCalendar(location *time.Location) *Calendar {
+	return &Calendar{
+		days:     make(map[string]*CalendarDay),
+		location: location,
+	}
+}
+
+// LoadCalendar reads a list of special days from a YAML file, or the events of an ICS file, which
+// are all treated as days to ignore.
+func LoadCalendar(path string, location *time.Location) (*Calendar, error) {
+	var days []*CalendarDay
+	var err error
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ics":
+		days, err = parseICSCalendar(path, location)
+	case ".yaml", ".yml":
+		days, err = parseYAMLCalendar(path)
+	default:
+		return nil, fmt.Errorf("unsupported calendar file: %s", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := newCalendar(location)
+	for _, day := range days {
+		if err := day.validate(); err != nil {
+			return nil, err
+		}
+		calendar.days[day.Date] = day
+	}
+	return calendar, nil
+}
+
+func (d *CalendarDay) validate() error {
+	if _, err := time.Parse(calendarDateLayout, d.Date); err != nil {
+		return fmt.Errorf("invalid calendar date: %s", d.Date)
+	}
+
+	switch d.Action {
+	case CalendarActionIgnore:
+	case CalendarActionSubstitute:
+		substitute, err := time.Parse(calendarDateLayout, d.Substitute)
+		if err != nil {
+			return fmt.Errorf("invalid substitute date for %s: %s", d.Date, d.Substitute)
+		}
+		d.substitute = substitute
+	case CalendarActionOverride:
+		if d.MinInstances == nil && d.MaxInstances == nil {
+			return fmt.Errorf("override for %s requires min_instances or max_instances", d.Date)
+		}
+	default:
+		return fmt.Errorf("unknown calendar action for %s: %s", d.Date, d.Action)
+	}
+	return nil
+}
+
+func parseYAMLCalendar(path string) ([]*CalendarDay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var days []*CalendarDay
+	if err := yaml.Unmarshal(data, &days); err != nil {
+		return nil, fmt.Errorf("failed to parse calendar %s: %v", path, err)
+	}
+	return days, nil
+}
+
+// parseICSCalendar reads the all-day and timed events of an ICS file, an event spanning several
+// days marks each of them. Timed events are mapped to the days of the business time zone. Yearly
+// recurring events are expanded, other recurrences are rejected.
+func parseICSCalendar(path string, location *time.Location) ([]*CalendarDay, error) {
+	lines, err := readICSLines(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var days []*CalendarDay
+	var start, end time.Time
+	var summary, rrule string
+	inEvent := false
+
+	for _, line := range lines {
+		name, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		// split off parameters like DTSTART;VALUE=DATE or DTSTART;TZID=Europe/Berlin
+		property, parameters, _ := strings.Cut(name, ";")
+
+		switch property {
+		case "BEGIN":
+			if value == "VEVENT" {
+				inEvent = true
+				start, end, summary, rrule = time.Time{}, time.Time{}, "", ""
+			}
+		case "DTSTART":
+			start, err = parseICSDate(value, parameters, location)
+		case "DTEND":
+			end, err = parseICSDate(value, parameters, location)
+		case "SUMMARY":
+			summary = value
+		case "RRULE":
+			rrule = value
+		case "END":
+			if value != "VEVENT" || !inEvent {
+				continue
+			}
+			inEvent = false
+			if start.IsZero() {
+				return nil, fmt.Errorf("event without DTSTART in %s", path)
+			}
+
+			var occurrences []time.Time
+			occurrences, err = icsOccurrences(start, rrule)
+			if err != nil {
+				return nil, fmt.Errorf("invalid event %s in %s: %v", summary, path, err)
+			}
+
+			// an event without DTEND lasts a single day
+			var duration time.Duration
+			if end.After(start) {
+				duration = end.Sub(start)
+			}
+			for _, occurrence := range occurrences {
+				days = append(days, icsEventDays(occurrence, occurrence.Add(duration), summary)...)
+			}
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse calendar %s: %v", path, err)
+		}
+	}
+
+	return days, nil
+}
+
+// icsEventDays returns the days the event touches, from the day it starts on up to its exclusive
+// end, a timed event passing midnight marks both days
+func icsEventDays(start time.Time, end time.Time, summary string) []*CalendarDay {
+	first := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	if !end.After(start) {
+		end = first.AddDate(0, 0, 1)
+	}
+
+	var days []*CalendarDay
+	for day := first; day.Before(end); day = day.AddDate(0, 0, 1) {
+		days = append(days, &CalendarDay{Date: day.Format(calendarDateLayout), Name: summary, Action: CalendarActionIgnore})
+	}
+	return days
+}
+
+// icsOccurrences returns the starts of an event, which recurs yearly on the date it starts on. Rules
+// that move the event to other dates, like the fourth Thursday of November, aren't supported.
+func icsOccurrences(start time.Time, rrule string) ([]time.Time, error) {
+	if rrule == "" {
+		return []time.Time{start}, nil
+	}
+
+	var yearly bool
+	var count int
+	var until time.Time
+	interval := 1
+
+	for _, part := range strings.Split(rrule, ";") {
+		key, value, _ := strings.Cut(part, "=")
+
+		var err error
+		switch key {
+		case "FREQ":
+			yearly = value == "YEARLY"
+		case "COUNT":
+			count, err = strconv.Atoi(value)
+		case "INTERVAL":
+			interval, err = strconv.Atoi(value)
+		case "UNTIL":
+			until, err = parseICSDate(value, "", start.Location())
+		case "BYMONTH":
+			// Outlook repeats the date of the start, which changes nothing
+			if value != strconv.Itoa(int(start.Month())) {
+				return nil, fmt.Errorf("unsupported recurrence: %s", rrule)
+			}
+		case "BYMONTHDAY":
+			if value != strconv.Itoa(start.Day()) {
+				return nil, fmt.Errorf("unsupported recurrence: %s", rrule)
+			}
+		case "WKST":
+		default:
+			return nil, fmt.Errorf("unsupported recurrence: %s", rrule)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid recurrence: %s", rrule)
+		}
+	}
+
+	if !yearly || interval < 1 {
+		return nil, fmt.Errorf("unsupported recurrence: %s", rrule)
+	}
+
+	lastYear := time.Now().Year() + calendarRecurrenceYears
+	var occurrences []time.Time
+	for years := 0; ; years += interval {
+		occurrence := start.AddDate(years, 0, 0)
+		if occurrence.Year() > lastYear || (count > 0 && len(occurrences) >= count) || (!until.IsZero() && occurrence.After(until)) {
+			break
+		}
+		occurrences = append(occurrences, occurrence)
+	}
+	return occurrences, nil
+}
+
+// readICSLines reads the content lines of an ICS file, unfolding long lines that continue on the
+// next line after a leading space or tab
+func readICSLines(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// parseICSDate parses a date or a date-time in UTC, in the time zone of its TZID parameter or as
+// floating time in the business time zone. Time zone names Go doesn't know, like the Windows names
+// of Outlook exports, fall back to the business time zone as well.
+func parseICSDate(value string, parameters string, location *time.Location) (time.Time, error) {
+	if len(value) == len("20060102") {
+		return time.Parse("20060102", value)
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t.In(location), err
+	}
+
+	valueLocation := location
+	for _, parameter := range strings.Split(parameters, ";") {
+		if tzid, found := strings.CutPrefix(parameter, "TZID="); found {
+			if tzLocation, err := time.LoadLocation(strings.Trim(tzid, `"`)); err == nil {
+				valueLocation = tzLocation
+			}
+		}
+	}
+
+	t, err := time.ParseInLocation("20060102T150405", value, valueLocation)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date: %s", value)
+	}
+	return t.In(location), nil
+}
+
+func (c *Calendar) day(t time.Time) *CalendarDay {
+	return c.days[t.In(c.location).Format(calendarDateLayout)]
+}
+
+// historicTime maps a point in the past to the point whose history should be used instead. It
+// returns false if the day is ignored.
+func (c *Calendar) historicTime(t time.Time) (time.Time, bool) {
+	day := c.day(t)
+	if day == nil {
+		return t, true
+	}
+
+	switch day.Action {
+	case CalendarActionIgnore:
+		return t, false
+	case CalendarActionSubstitute:
+		local := t.In(c.location)
+		substitute := time.Date(day.substitute.Year(), day.substitute.Month(), day.substitute.Day(),
+			local.Hour(), local.Minute(), local.Second(), local.Nanosecond(), c.location)
+		return substitute.In(time.UTC), true
+	default:
+		return t, true
+	}
+}
+
+// sameTimeWeeksAgo returns the same wall-clock time the given number of weeks ago
+func (c *Calendar) sameTimeWeeksAgo(now time.Time, weeks int) time.Time {
+	return metrics.SameTimeWeeksAgo(now, weeks, c.location)
+}
+
+// weeksAgo returns the same time the given number of weeks ago, stepping back further while the
+// day is ignored
+func (c *Calendar) weeksAgo(now time.Time, weeks int) (time.Time, bool) {
+	for lookback := weeks; lookback < weeks+calendarMaxLookbackWeeks; lookback++ {
+		if t, ok := c.historicTime(c.sameTimeWeeksAgo(now, lookback)); ok {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// instanceLimits applies an override of the day to the given limits
+func (c *Calendar) instanceLimits(t time.Time, minInstances, maxInstances uint) (uint, uint) {
+	day := c.day(t)
+	if day == nil || day.Action != CalendarActionOverride {
+		return minInstances, maxInstances
+	}
+
+	if day.MinInstances != nil {
+		minInstances = *day.MinInstances
+	}
+	if day.MaxInstances != nil {
+		maxInstances = *day.MaxInstances
+	}
+	return minInstances, maxInstances
+}
+
+// cleanSeries replaces the values of ignored days with the ones of the previous week and the values
+// of substituted days with the ones of the substitute date, if they are part of the series
+func (c *Calendar) cleanSeries(series []float64, start time.Time, period time.Duration) {
+	if len(c.days) == 0 {
+		return
+	}
+
+	weekSlots := int(7 * 24 * time.Hour / period)
+	for i := range series {
+		t := start.Add(time.Duration(i) * period)
+		historic, ok := c.historicTime(t)
+		if !ok {
+			if i >= weekSlots {
+				series[i] = series[i-weekSlots]
+			}
+			continue
+		}
+
+		if j := int(historic.Sub(start) / period); j != i && j >= 0 && j < len(series) {
+			series[i] = series[j]
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package scaler
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseICSDate(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		value      string
+		parameters string
+		expected   string
+		wantErr    bool
+	}{
+		{name: "date only", value: "20261225", parameters: "VALUE=DATE", expected: "2026-12-25 00:00"},
+		{name: "utc", value: "20261224T230000Z", expected: "2026-12-25 00:00"},
+		{name: "tzid", value: "20261224T200000", parameters: "TZID=America/New_York", expected: "2026-12-25 02:00"},
+		{name: "quoted tzid", value: "20261224T200000", parameters: `TZID="America/New_York"`, expected: "2026-12-25 02:00"},
+		{name: "floating", value: "20261224T200000", expected: "2026-12-24 20:00"},
+		{name: "unknown tzid", value: "20261224T200000", parameters: "TZID=Nowhere/Special", expected: "2026-12-24 20:00"},
+		{name: "invalid", value: "2026-12-24", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			date, err := parseICSDate(tt.value, tt.parameters, berlin)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %s", date)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := date.Format("2006-01-02 15:04"); got != tt.expected {
+				t.Errorf("parseICSDate(%s, %s) = %s, expected %s", tt.value, tt.parameters, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseICSCalendar(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		event    string
+		expected []string
+		wantErr  bool
+	}{
+		{
+			name:     "all-day event",
+			event:    "DTSTART;VALUE=DATE:20261225\nDTEND;VALUE=DATE:20261227",
+			expected: []string{"2026-12-25", "2026-12-26"},
+		},
+		{
+			name:     "event without end",
+			event:    "DTSTART;VALUE=DATE:20261231",
+			expected: []string{"2026-12-31"},
+		},
+		{
+			name:     "timed event crossing midnight",
+			event:    "DTSTART;TZID=Europe/Berlin:20261224T220000\nDTEND;TZID=Europe/Berlin:20261225T020000",
+			expected: []string{"2026-12-24", "2026-12-25"},
+		},
+		{
+			name:     "timed event ending at midnight",
+			event:    "DTSTART:20261224T200000Z\nDTEND:20261224T230000Z",
+			expected: []string{"2026-12-24"},
+		},
+		{
+			name:     "yearly recurrence",
+			event:    "DTSTART;VALUE=DATE:20240101\nRRULE:FREQ=YEARLY;COUNT=3",
+			expected: []string{"2024-01-01", "2025-01-01", "2026-01-01"},
+		},
+		{
+			name:     "yearly recurrence until",
+			event:    "DTSTART;VALUE=DATE:20241225\nDTEND;VALUE=DATE:20241227\nRRULE:FREQ=YEARLY;UNTIL=20251231;BYMONTH=12;BYMONTHDAY=25",
+			expected: []string{"2024-12-25", "2024-12-26", "2025-12-25", "2025-12-26"},
+		},
+		{
+			name:    "unsupported recurrence",
+			event:   "DTSTART;VALUE=DATE:20261126\nRRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=4TH",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "calendar.ics")
+			content := "BEGIN:VCALENDAR\nBEGIN:VEVENT\nSUMMARY:Holiday\n" + tt.event + "\nEND:VEVENT\nEND:VCALENDAR\n"
+			if err := os.WriteFile(path, []byte(strings.ReplaceAll(content, "\n", "\r\n")), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			days, err := parseICSCalendar(path, berlin)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %d days", len(days))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var dates []string
+			for _, day := range days {
+				dates = append(dates, day.Date)
+			}
+			if strings.Join(dates, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("got days %v, expected %v", dates, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package scaler
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+	"predictive-rds-scaler/types"
+)
+
+// LoadClusterConfigs reads a YAML list of cluster definitions. Each definition overrides the given
+// defaults with its own cluster name, targets, limits and schedules.
+func LoadClusterConfigs(path string, defaults types.Config) ([]*types.Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []yaml.Node
+	if err := yaml.Unmarshal(data, &nodes); err != nil {
+		return nil, fmt.Errorf("failed to parse clusters %s: %v", path, err)
+	}
+
+	configs := make([]*types.Config, 0, len(nodes))
+	clusterNames := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		conf := defaults
+		if err := node.Decode(&conf); err != nil {
+			return nil, fmt.Errorf("failed to parse clusters %s: %v", path, err)
+		}
+
+		if conf.RdsClusterName == "" {
+			return nil, fmt.Errorf("cluster definition in %s requires rds_cluster_name", path)
+		}
+		if clusterNames[conf.RdsClusterName] {
+			return nil, fmt.Errorf("duplicate cluster definition in %s: %s", path, conf.RdsClusterName)
+		}
+		clusterNames[conf.RdsClusterName] = true
+
+		configs = append(configs, &conf)
+	}
+
+	if len(configs) == 0 {
+		return nil, fmt.Errorf("no clusters defined in %s", path)
+	}
+	return configs, nil
+}
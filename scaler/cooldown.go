@@ -0,0 +1,106 @@
+package scaler
+
+import (
+	"time"
+)
+
+type sizeRecommendation struct {
+	timestamp time.Time
+	size      uint
+}
+
+type capacityRecommendation struct {
+	timestamp time.Time
+	capacity  float64
+}
+
+// recordRecommendation remembers the desired size and updates the size scale in is stabilized to,
+// the highest desired size within the stabilization window
+func (s *Scaler) recordRecommendation(now time.Time, size uint) {
+	if s.recommendationsSince.IsZero() {
+		s.recommendationsSince = now
+	}
+
+	windowStart := now.Add(-1 * s.config.ScaleInStabilizationWindow)
+	recommendations := s.recommendations[:0]
+	for _, recommendation := range s.recommendations {
+		if recommendation.timestamp.After(windowStart) {
+			recommendations = append(recommendations, recommendation)
+		}
+	}
+	s.recommendations = append(recommendations, sizeRecommendation{timestamp: now, size: size})
+
+	threshold := size
+	for _, recommendation := range s.recommendations {
+		threshold = max(threshold, recommendation.size)
+	}
+
+	s.scalerStatus.Threshold = threshold
+	s.scalerStatus.IsStable = !s.recommendationsSince.After(windowStart)
+}
+
+// stabilizedScaleInSize returns the size the cluster may be scaled in to, it is false while the
+// desired size hasn't been lower than the current size for the whole stabilization window
+func (s *Scaler) stabilizedScaleInSize(currentSize uint) (uint, bool) {
+	if !s.scalerStatus.IsStable || s.scalerStatus.Threshold >= currentSize {
+		return currentSize, false
+	}
+	return s.scalerStatus.Threshold, true
+}
+
+// recordCapacityRecommendation remembers the required serverless minimum capacity, like
+// recordRecommendation does for the number of readers
+func (s *Scaler) recordCapacityRecommendation(now time.Time, capacity float64) {
+	if s.capacityRecommendationsSince.IsZero() {
+		s.capacityRecommendationsSince = now
+	}
+
+	windowStart := now.Add(-1 * s.config.ScaleInStabilizationWindow)
+	recommendations := s.capacityRecommendations[:0]
+	for _, recommendation := range s.capacityRecommendations {
+		if recommendation.timestamp.After(windowStart) {
+			recommendations = append(recommendations, recommendation)
+		}
+	}
+	s.capacityRecommendations = append(recommendations, capacityRecommendation{timestamp: now, capacity: capacity})
+}
+
+// stabilizedScaleInCapacity returns the capacity the serverless minimum may be lowered to, the highest
+// required capacity within the stabilization window. It is false while the window isn't covered yet
+// or no lower capacity was required throughout it.
+func (s *Scaler) stabilizedScaleInCapacity(now time.Time, currentCapacity float64) (float64, bool) {
+	if s.capacityRecommendationsSince.After(now.Add(-1 * s.config.ScaleInStabilizationWindow)) {
+		return currentCapacity, false
+	}
+
+	threshold := 0.0
+	for _, recommendation := range s.capacityRecommendations {
+		threshold = max(threshold, recommendation.capacity)
+	}
+	if threshold >= currentCapacity {
+		return currentCapacity, false
+	}
+	return threshold, true
+}
+
+func (s *Scaler) isScaleOutCoolingDown(now time.Time) bool {
+	return now.Before(s.scalerStatus.ScaleOutTimeout)
+}
+
+func (s *Scaler) isScaleInCoolingDown(now time.Time) bool {
+	return now.Before(s.scalerStatus.ScaleInTimeout)
+}
+
+// startScaleOutCooldown is called once new readers are available, a scale in right after would
+// remove the capacity that was just added
+func (s *Scaler) startScaleOutCooldown(now time.Time) {
+	s.scalerStatus.LastScale = now
+	s.scalerStatus.ScaleOutTimeout = now.Add(s.config.ScaleOutCooldown)
+	s.scalerStatus.ScaleInTimeout = now.Add(s.config.ScaleInCooldown)
+}
+
+// startScaleInCooldown is called once readers are deleted, scaling out stays possible right away
+func (s *Scaler) startScaleInCooldown(now time.Time) {
+	s.scalerStatus.LastScale = now
+	s.scalerStatus.ScaleInTimeout = now.Add(s.config.ScaleInCooldown)
+}
@@ -0,0 +1,72 @@
+package scaler
+
+import (
+	"fmt"
+	"predictive-rds-scaler/types"
+	"time"
+)
+
+const (
+	ActionCreateReaders               = "create_readers"
+	ActionDeleteInstance              = "delete_instance"
+	ActionModifyInstanceClass         = "modify_instance_class"
+	ActionModifyServerlessMinCapacity = "modify_serverless_min_capacity"
+	ActionFailback                    = "failback"
+	ActionAdoptInstance               = "adopt_instance"
+)
+
+// startPlanning starts a new scaling run, actions that were already planned in the previous run
+// aren't reported again
+func (s *Scaler) startPlanning() {
+	s.previousPlannedActions, s.plannedActions = s.plannedActions, nil
+}
+
+// planAction reports a change the scaler would make instead of applying it, used in dry-run mode
+func (s *Scaler) planAction(action types.PlannedAction) {
+	s.plannedActions = append(s.plannedActions, action)
+	for _, previous := range s.previousPlannedActions {
+		if previous == action {
+			return
+		}
+	}
+
+	action.Timestamp = time.Now().In(time.UTC)
+
+	s.logger.Info().
+		Str("Action", action.Action).
+		Str("InstanceIdentifier", action.InstanceIdentifier).
+		Str("InstanceClass", action.InstanceClass).
+		Uint("Count", action.Count).
+		Float64("MinCapacity", action.MinCapacity).
+		Msg("Dry run: skipping action")
+
+	s.submitBroadcast(&types.Broadcast{MessageType: "plannedAction", Data: action})
+}
+
+// planScaleOut reports the readers the scale out would create, with the instance class they would
+// be created with
+func (s *Scaler) planScaleOut(plans []readerPlan) error {
+	for _, plan := range plans {
+		if plan.count == 0 {
+			continue
+		}
+
+		instanceClasses := plan.instanceClasses
+		if len(instanceClasses) == 0 {
+			writerInstance, err := s.getWriterInstance()
+			if err != nil {
+				return fmt.Errorf("failed to get current writer instance: %v", err)
+			}
+			instanceClasses = s.readerInstanceClassesFor(writerInstance)
+		}
+
+		s.planAction(types.PlannedAction{
+			Action:        ActionCreateReaders,
+			InstanceClass: instanceClasses[0],
+			Count:         plan.count,
+		})
+	}
+
+	s.startScaleOutCooldown(time.Now().In(time.UTC))
+	return nil
+}
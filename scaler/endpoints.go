@@ -0,0 +1,87 @@
+package scaler
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+// addToCustomEndpoints adds new readers to the static members of the configured custom endpoints
+func (s *Scaler) addToCustomEndpoints(instanceIdentifiers []string) error {
+	for _, endpoint := range s.customEndpoints {
+		err := s.updateCustomEndpoint(endpoint, func(members []string) []string {
+			for _, instanceIdentifier := range instanceIdentifiers {
+				if !containsString(members, instanceIdentifier) {
+					members = append(members, instanceIdentifier)
+				}
+			}
+			return members
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeFromCustomEndpoints removes a reader from the configured custom endpoints before it is
+// deleted, so no new connections are routed to it
+func (s *Scaler) removeFromCustomEndpoints(instanceIdentifier string) error {
+	for _, endpoint := range s.customEndpoints {
+		err := s.updateCustomEndpoint(endpoint, func(members []string) []string {
+			remaining := make([]string, 0, len(members))
+			for _, member := range members {
+				if member != instanceIdentifier {
+					remaining = append(remaining, member)
+				}
+			}
+			return remaining
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateCustomEndpoint replaces the static members of an endpoint. Endpoints without static members
+// include every instance that isn't excluded, new readers join those on their own.
+func (s *Scaler) updateCustomEndpoint(endpoint string, update func(members []string) []string) error {
+	describeOutput, err := s.rdsClient.DescribeDBClusterEndpoints(&rds.DescribeDBClusterEndpointsInput{
+		DBClusterIdentifier:         aws.String(s.config.RdsClusterName),
+		DBClusterEndpointIdentifier: aws.String(endpoint),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe custom endpoint %s: %v", endpoint, err)
+	}
+
+	if len(describeOutput.DBClusterEndpoints) == 0 {
+		return fmt.Errorf("custom endpoint not found: %s", endpoint)
+	}
+
+	members := aws.StringValueSlice(describeOutput.DBClusterEndpoints[0].StaticMembers)
+	if len(members) == 0 {
+		return nil
+	}
+
+	updatedMembers := update(members)
+	if len(updatedMembers) == len(members) {
+		return nil
+	}
+	if len(updatedMembers) == 0 {
+		// without static members the endpoint would route to every instance of the cluster
+		s.logger.Warn().Str("Endpoint", endpoint).Msg("Keeping the last static member of the custom endpoint")
+		return nil
+	}
+
+	_, err = s.rdsClient.ModifyDBClusterEndpoint(&rds.ModifyDBClusterEndpointInput{
+		DBClusterEndpointIdentifier: aws.String(endpoint),
+		StaticMembers:               aws.StringSlice(updatedMembers),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to modify custom endpoint %s: %v", endpoint, err)
+	}
+
+	s.logger.Info().Str("Endpoint", endpoint).Strs("StaticMembers", updatedMembers).Msg("Custom endpoint updated")
+	return nil
+}
@@ -0,0 +1,118 @@
+package scaler
+
+import (
+	"fmt"
+	"math"
+	"predictive-rds-scaler/metrics"
+	"predictive-rds-scaler/types"
+	"time"
+)
+
+// errors below this floor (in percentage points) don't earn a predictor any more weight, so one
+// lucky streak can't silence the other predictors entirely
+const ensembleMinError = 0.5
+
+// ensemblePredictor runs several predictors side by side and blends their predictions, weighting
+// each by the inverse of its mean absolute error over the trailing window.
+type ensemblePredictor struct {
+	config   *types.Config
+	metrics  *metrics.Metrics
+	accuracy *accuracyTracker
+	members  []Predictor
+	window   time.Duration
+}
+
+func newEnsemblePredictor(conf *types.Config, cloudwatchMetrics *metrics.Metrics, calendar *Calendar, accuracy *accuracyTracker) (*ensemblePredictor, error) {
+	if conf.EnsemblePredictors == "" {
+		return nil, fmt.Errorf("ensemble predictor requires at least one member predictor")
+	}
+
+	var members []Predictor
+	for _, name := range splitAndTrimStrings(conf.EnsemblePredictors, ",") {
+		if name == PredictorEnsemble {
+			return nil, fmt.Errorf("ensemble predictor can't contain itself")
+		}
+
+		member, err := newPredictorByName(name, conf, cloudwatchMetrics, calendar, accuracy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create ensemble member: %w", err)
+		}
+		members = append(members, member)
+	}
+
+	return &ensemblePredictor{
+		config:   conf,
+		metrics:  cloudwatchMetrics,
+		accuracy: accuracy,
+		members:  members,
+		window:   conf.EnsembleWindow,
+	}, nil
+}
+
+func (p *ensemblePredictor) Name() string {
+	return PredictorEnsemble
+}
+
+func (p *ensemblePredictor) Predict(now time.Time, horizon time.Duration) (*types.ClusterStatus, error) {
+	return p.blend(now, now.Add(horizon), func(member Predictor) (*types.ClusterStatus, error) {
+		prediction, err := member.Predict(now, horizon)
+		if err == nil {
+			// every member is scored on its own, that is where the weights come from
+			p.accuracy.record(member.Name(), now.Add(horizon), prediction)
+		}
+		return prediction, err
+	})
+}
+
+func (p *ensemblePredictor) PredictWindow(now time.Time, offset time.Duration, window time.Duration) (*types.ClusterStatus, error) {
+	return p.blend(now, now.Add(offset+window), func(member Predictor) (*types.ClusterStatus, error) {
+		return predictAt(member, now, offset, window)
+	})
+}
+
+// blend combines the predictions of the members for the target time, weighted by their accuracy
+func (p *ensemblePredictor) blend(now time.Time, target time.Time, predict func(member Predictor) (*types.ClusterStatus, error)) (*types.ClusterStatus, error) {
+	var loads, readers, weights []float64
+	var unscored []int
+	var scoredWeightSum float64
+
+	for _, member := range p.members {
+		prediction, err := predict(member)
+		if err != nil {
+			continue
+		}
+
+		loads = append(loads, prediction.AverageCPUUtilization*float64(prediction.CurrentActiveReaders))
+		readers = append(readers, float64(prediction.CurrentActiveReaders))
+
+		meanAbsoluteError, samples := p.accuracy.meanAbsoluteError(member.Name(), now, p.window)
+		if samples == 0 {
+			unscored = append(unscored, len(weights))
+			weights = append(weights, 0)
+			continue
+		}
+
+		weight := 1 / math.Max(meanAbsoluteError, ensembleMinError)
+		weights = append(weights, weight)
+		scoredWeightSum += weight
+	}
+
+	if len(loads) == 0 {
+		return nil, fmt.Errorf("none of the ensemble members made a prediction")
+	}
+
+	// members without a track record yet get the average weight of the scored ones, or an equal
+	// share if nobody has been scored so far
+	unscoredWeight := 1.0
+	if scored := len(weights) - len(unscored); scored > 0 {
+		unscoredWeight = scoredWeightSum / float64(scored)
+	}
+	for _, i := range unscored {
+		weights[i] = unscoredWeight
+	}
+
+	load := weightedMean(loads, weights)
+	readerCount := weightedMean(readers, weights)
+
+	return newPredictedStatus(p.config, p.metrics, target, load, readerCount), nil
+}
@@ -0,0 +1,175 @@
+package scaler
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"predictive-rds-scaler/types"
+	"sort"
+	"time"
+)
+
+const (
+	FailoverActionNone     = "none"
+	FailoverActionFailback = "failback"
+	FailoverActionAdopt    = "adopt"
+
+	// marks a former managed reader that is kept as a permanent instance after it became the writer
+	adoptedTagKey = "predictive-rds-scaler:adopted"
+
+	// how long a failback may take before the scaler resumes scaling
+	failbackTimeout = 10 * time.Minute
+)
+
+// isManagedInstance reports whether the instance is a reader created by the scaler that wasn't
+// adopted as a permanent instance
+func (s *Scaler) isManagedInstance(instance *rds.DBInstance) bool {
+	return s.isOwnedInstance(instance) && !isAdoptedInstance(instance)
+}
+
+// isAdoptedInstance reports whether the instance was adopted as a permanent instance
+func isAdoptedInstance(instance *rds.DBInstance) bool {
+	for _, tag := range instance.TagList {
+		if aws.StringValue(tag.Key) == adoptedTagKey {
+			return true
+		}
+	}
+	return false
+}
+
+// checkManagedWriter detects a managed reader that was promoted to writer by a failover, which the
+// scaler could never scale in, and either fails back to a permanent reader or adopts it
+func (s *Scaler) checkManagedWriter(clusterStatus *types.ClusterStatus) {
+	var writerIdentifier string
+	var isManaged bool
+	for _, instance := range clusterStatus.Instances {
+		if instance.IsWriter {
+			writerIdentifier = instance.Identifier
+			isManaged = instance.IsManaged
+		}
+	}
+
+	if s.config.FailoverAction == FailoverActionNone || !isManaged {
+		return
+	}
+
+	writerInstance, err := s.getWriterInstance()
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Error getting writer instance")
+		return
+	}
+
+	s.logger.Warn().Str("InstanceIdentifier", writerIdentifier).Str("Action", s.config.FailoverAction).Msg("Managed reader became the writer")
+
+	if s.config.DryRun {
+		action := ActionFailback
+		if s.config.FailoverAction == FailoverActionAdopt {
+			action = ActionAdoptInstance
+		}
+		s.planAction(types.PlannedAction{Action: action, InstanceIdentifier: writerIdentifier})
+		return
+	}
+
+	switch s.config.FailoverAction {
+	case FailoverActionAdopt:
+		err = s.adoptInstance(writerInstance)
+	case FailoverActionFailback:
+		err = s.failback()
+	}
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Error rebalancing after failover")
+	}
+}
+
+// adoptInstance keeps the instance as a permanent member of the cluster, it isn't scaled in anymore
+func (s *Scaler) adoptInstance(instance *rds.DBInstance) error {
+	_, err := s.rdsClient.AddTagsToResource(&rds.AddTagsToResourceInput{
+		ResourceName: instance.DBInstanceArn,
+		Tags: []*rds.Tag{
+			{
+				Key:   aws.String(adoptedTagKey),
+				Value: aws.String(time.Now().In(time.UTC).Format(time.RFC3339)),
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to adopt instance %s: %v", aws.StringValue(instance.DBInstanceIdentifier), err)
+	}
+
+	s.logger.Info().Str("InstanceIdentifier", aws.StringValue(instance.DBInstanceIdentifier)).Msg("Instance adopted as permanent writer")
+	return nil
+}
+
+// failback fails over to the permanent reader with the highest failover priority
+func (s *Scaler) failback() error {
+	if s.scalerStatus.IsScaling {
+		s.logger.Info().Msg("Skipping failback: Scaling operation already in progress")
+		return nil
+	}
+
+	readerInstances, err := s.getReaderInstances(StatusAvailable)
+	if err != nil {
+		return err
+	}
+
+	var candidates []*rds.DBInstance
+	for _, instance := range readerInstances {
+		if !s.isManagedInstance(instance) {
+			candidates = append(candidates, instance)
+		}
+	}
+	if len(candidates) == 0 {
+		return fmt.Errorf("no permanent reader available to fail back to")
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return aws.Int64Value(candidates[i].PromotionTier) < aws.Int64Value(candidates[j].PromotionTier)
+	})
+	target := aws.StringValue(candidates[0].DBInstanceIdentifier)
+
+	s.scalerStatus.IsScaling = true
+
+	_, err = s.rdsClient.FailoverDBCluster(&rds.FailoverDBClusterInput{
+		DBClusterIdentifier:        aws.String(s.config.RdsClusterName),
+		TargetDBInstanceIdentifier: aws.String(target),
+	})
+	if err != nil {
+		s.scalerStatus.IsScaling = false
+		return fmt.Errorf("failed to fail back to %s: %v", target, err)
+	}
+
+	s.logger.Info().Str("TargetInstanceIdentifier", target).Msg("Failing back to permanent reader")
+
+	go func() {
+		err := s.waitForWriter(target)
+		s.scalerStatus.IsScaling = false
+		if err != nil {
+			s.logger.Error().Err(err).Msg("Error waiting for failback")
+		}
+	}()
+
+	return nil
+}
+
+// waitForWriter waits until the instance is the available writer, a stuck failover is given up on
+// after the failback timeout
+func (s *Scaler) waitForWriter(instanceIdentifier string) error {
+	deadline := time.Now().Add(failbackTimeout)
+	for {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("instance %s didn't become the writer within %s", instanceIdentifier, failbackTimeout)
+		}
+
+		writerInstance, err := s.getWriterInstance()
+		if err != nil {
+			return err
+		}
+
+		if aws.StringValue(writerInstance.DBInstanceIdentifier) == instanceIdentifier && aws.StringValue(writerInstance.DBInstanceStatus) == "available" {
+			return nil
+		}
+
+		s.logger.Info().Str("InstanceIdentifier", instanceIdentifier).Msg("Waiting for instance to become the writer")
+		time.Sleep(5 * time.Second)
+	}
+}
@@ -2,6 +2,8 @@ package scaler
 
 import (
 	"fmt"
+	"predictive-rds-scaler/types"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -15,6 +17,15 @@ func containsString(list []string, str string) bool {
 	return false
 }
 
+func indexOfString(list []string, str string) int {
+	for i, s := range list {
+		if s == str {
+			return i
+		}
+	}
+	return -1
+}
+
 func splitAndTrimStrings(input, sep string) []string {
 	items := strings.Split(input, sep)
 	for i, item := range items {
@@ -23,15 +34,6 @@ func splitAndTrimStrings(input, sep string) []string {
 	return items
 }
 
-func isBoostHour(hour int, scaleOutHours []int) bool {
-	for _, h := range scaleOutHours {
-		if hour == h {
-			return true
-		}
-	}
-	return false
-}
-
 func parseBoostHours(scaleOutHoursStr string) ([]int, error) {
 	if scaleOutHoursStr == "" {
 		return nil, nil // Return nil to indicate no boost hours specified
@@ -48,3 +50,99 @@ func parseBoostHours(scaleOutHoursStr string) ([]int, error) {
 	}
 	return scaleOutHours, nil
 }
+
+func weightedMean(values, weights []float64) float64 {
+	var sum, totalWeight float64
+	for i, value := range values {
+		sum += value * weights[i]
+		totalWeight += weights[i]
+	}
+	if totalWeight == 0 {
+		return mean(values)
+	}
+	return sum / totalWeight
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+	return sum / float64(len(values))
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+// averageInstanceMetrics averages each scaling metric over the instances reporting it
+func averageInstanceMetrics(instances []types.InstanceStatus) map[string]float64 {
+	sums := make(map[string]float64)
+	counts := make(map[string]int)
+	for _, instance := range instances {
+		for metricName, value := range instance.Metrics {
+			sums[metricName] += value
+			counts[metricName]++
+		}
+	}
+
+	averages := make(map[string]float64, len(sums))
+	for metricName, sum := range sums {
+		averages[metricName] = sum / float64(counts[metricName])
+	}
+	return averages
+}
+
+// averageInstanceUtilization weights the CPU utilization of each instance by its vCPUs and returns
+// the total vCPUs, which is 0 and the plain average is returned if the capacity of any instance is
+// unknown
+func averageInstanceUtilization(instances []types.InstanceStatus) (float64, float64) {
+	var totalUtilization, weightedUtilization, totalVCPUs float64
+	for _, instance := range instances {
+		totalUtilization += instance.CPUUtilization
+		weightedUtilization += instance.CPUUtilization * instance.VCPUs
+		totalVCPUs += instance.VCPUs
+	}
+
+	for _, instance := range instances {
+		if instance.VCPUs == 0 {
+			return totalUtilization / float64(len(instances)), 0
+		}
+	}
+	return weightedUtilization / totalVCPUs, totalVCPUs
+}
+
+// parseReaderInstanceClasses returns the configured reader instance class followed by its fallbacks
+func parseReaderInstanceClasses(instanceClass string, fallbacks string) []string {
+	var instanceClasses []string
+	for _, item := range append([]string{instanceClass}, splitAndTrimStrings(fallbacks, ",")...) {
+		if item != "" && !containsString(instanceClasses, item) {
+			instanceClasses = append(instanceClasses, item)
+		}
+	}
+	return instanceClasses
+}
+
+// parseList parses a comma-separated list, leaving out empty items
+func parseList(listStr string) []string {
+	var items []string
+	for _, item := range splitAndTrimStrings(listStr, ",") {
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,206 @@
+package scaler
+
+import (
+	"fmt"
+	"math"
+	"predictive-rds-scaler/metrics"
+	"predictive-rds-scaler/types"
+	"sync"
+	"time"
+)
+
+const (
+	holtWintersPeriod      = 5 * time.Minute
+	holtWintersDailySlots  = int(24 * time.Hour / holtWintersPeriod)
+	holtWintersWeeklySlots = 7 * holtWintersDailySlots
+)
+
+// holtWintersPredictor fits a triple exponential smoothing model with daily and weekly seasonality
+// (Taylor's double seasonal Holt-Winters) to the cluster load and extrapolates it, which lets the
+// prediction follow growth trends instead of replaying the past.
+type holtWintersPredictor struct {
+	config   *types.Config
+	metrics  *metrics.Metrics
+	calendar *Calendar
+
+	mutex      sync.Mutex
+	model      *holtWintersModel
+	fittedAt   time.Time
+	lastSample time.Time
+	readers    float64
+}
+
+type holtWintersModel struct {
+	alpha, beta, gamma, delta float64
+
+	level   float64
+	trend   float64
+	daily   []float64
+	weekly  []float64
+	samples int
+}
+
+func newHoltWintersPredictor(conf *types.Config, cloudwatchMetrics *metrics.Metrics, calendar *Calendar) (*holtWintersPredictor, error) {
+	if conf.HoltWintersHistory <= 2*7*24*time.Hour {
+		return nil, fmt.Errorf("holt-winters predictor requires more than two weeks of history, got %s", conf.HoltWintersHistory)
+	}
+
+	for name, value := range map[string]float64{
+		"alpha": conf.HoltWintersAlpha,
+		"beta":  conf.HoltWintersBeta,
+		"gamma": conf.HoltWintersGamma,
+		"delta": conf.HoltWintersDelta,
+	} {
+		if value < 0 || value > 1 {
+			return nil, fmt.Errorf("holt-winters %s must be between 0 and 1, got %f", name, value)
+		}
+	}
+
+	return &holtWintersPredictor{
+		config:   conf,
+		metrics:  cloudwatchMetrics,
+		calendar: calendar,
+	}, nil
+}
+
+func (p *holtWintersPredictor) Name() string {
+	return PredictorHoltWinters
+}
+
+func (p *holtWintersPredictor) Predict(now time.Time, horizon time.Duration) (*types.ClusterStatus, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	// CloudWatch only provides a new data point every period, refitting more often is wasted effort
+	if p.model == nil || now.Sub(p.fittedAt) >= holtWintersPeriod {
+		if err := p.fit(now); err != nil {
+			return nil, err
+		}
+	}
+
+	steps := int(math.Ceil(float64(now.Add(horizon).Sub(p.lastSample)) / float64(holtWintersPeriod)))
+	load := p.model.forecast(max(1, steps))
+
+	return newPredictedStatus(p.config, p.metrics, now.Add(horizon), load, p.readers), nil
+}
+
+func (p *holtWintersPredictor) fit(now time.Time) error {
+	start := now.Add(-1 * p.config.HoltWintersHistory)
+	statusHistory, err := p.metrics.GetClusterStatus(start, now, holtWintersPeriod)
+	if err != nil {
+		return err
+	}
+
+	if len(statusHistory) == 0 {
+		return fmt.Errorf("no cluster status history available")
+	}
+
+	series := make([]float64, 0, len(statusHistory))
+	for _, status := range statusHistory {
+		series = append(series, status.AverageCPUUtilization*float64(status.CurrentActiveReaders))
+	}
+	series = fillSeriesGaps(statusHistory, series, holtWintersPeriod)
+	p.calendar.cleanSeries(series, statusHistory[0].Timestamp, holtWintersPeriod)
+
+	model, err := fitHoltWinters(series, p.config.HoltWintersAlpha, p.config.HoltWintersBeta, p.config.HoltWintersGamma, p.config.HoltWintersDelta)
+	if err != nil {
+		return err
+	}
+
+	lastStatus := statusHistory[len(statusHistory)-1]
+	p.model = model
+	p.fittedAt = now
+	p.lastSample = lastStatus.Timestamp
+	p.readers = float64(lastStatus.CurrentActiveReaders)
+
+	return nil
+}
+
+// fillSeriesGaps places the values on a regular grid, CloudWatch omits periods without data points.
+// Missing values are carried forward from the previous data point.
+func fillSeriesGaps(statusHistory []*types.ClusterStatus, values []float64, period time.Duration) []float64 {
+	start := statusHistory[0].Timestamp
+	slots := int(statusHistory[len(statusHistory)-1].Timestamp.Sub(start)/period) + 1
+
+	series := make([]float64, slots)
+	filled := make([]bool, slots)
+	for i, status := range statusHistory {
+		slot := int(status.Timestamp.Sub(start) / period)
+		series[slot] = values[i]
+		filled[slot] = true
+	}
+
+	for i := 1; i < slots; i++ {
+		if !filled[i] {
+			series[i] = series[i-1]
+		}
+	}
+	return series
+}
+
+func fitHoltWinters(series []float64, alpha, beta, gamma, delta float64) (*holtWintersModel, error) {
+	if len(series) < 2*holtWintersWeeklySlots {
+		return nil, fmt.Errorf("holt-winters requires two weeks of data points, got %d of %d", len(series), 2*holtWintersWeeklySlots)
+	}
+
+	model := &holtWintersModel{
+		alpha:  alpha,
+		beta:   beta,
+		gamma:  gamma,
+		delta:  delta,
+		daily:  make([]float64, holtWintersDailySlots),
+		weekly: make([]float64, holtWintersWeeklySlots),
+	}
+
+	// initialize the components from the first two weeks, the level is moved back to the first
+	// sample so that the trend within the first week doesn't end up in the seasonal components
+	firstWeek := mean(series[:holtWintersWeeklySlots])
+	secondWeek := mean(series[holtWintersWeeklySlots : 2*holtWintersWeeklySlots])
+	model.trend = (secondWeek - firstWeek) / float64(holtWintersWeeklySlots)
+	model.level = firstWeek - model.trend*float64(holtWintersWeeklySlots-1)/2
+
+	for day := 0; day < 7; day++ {
+		dayOffset := day * holtWintersDailySlots
+		dayMean := mean(series[dayOffset : dayOffset+holtWintersDailySlots])
+		for slot := 0; slot < holtWintersDailySlots; slot++ {
+			detrended := series[dayOffset+slot] - model.trend*float64(slot-(holtWintersDailySlots-1)/2)
+			model.daily[slot] += (detrended - dayMean) / 7
+		}
+	}
+
+	for slot := 0; slot < holtWintersWeeklySlots; slot++ {
+		baseline := model.level + model.trend*float64(slot)
+		model.weekly[slot] = series[slot] - baseline - model.daily[slot%holtWintersDailySlots]
+	}
+
+	// the fit starts one period before the first sample
+	model.level -= model.trend
+
+	for _, value := range series {
+		model.update(value)
+	}
+
+	return model, nil
+}
+
+func (m *holtWintersModel) update(value float64) {
+	dailySlot := m.samples % holtWintersDailySlots
+	weeklySlot := m.samples % holtWintersWeeklySlots
+	daily := m.daily[dailySlot]
+	weekly := m.weekly[weeklySlot]
+
+	previousLevel := m.level
+	m.level = m.alpha*(value-daily-weekly) + (1-m.alpha)*(m.level+m.trend)
+	m.trend = m.beta*(m.level-previousLevel) + (1-m.beta)*m.trend
+	m.daily[dailySlot] = m.gamma*(value-m.level-weekly) + (1-m.gamma)*daily
+	m.weekly[weeklySlot] = m.delta*(value-m.level-daily) + (1-m.delta)*weekly
+	m.samples++
+}
+
+// forecast returns the expected value the given number of periods after the last sample
+func (m *holtWintersModel) forecast(steps int) float64 {
+	slot := m.samples + steps - 1
+	return m.level + float64(steps)*m.trend +
+		m.daily[slot%holtWintersDailySlots] +
+		m.weekly[slot%holtWintersWeeklySlots]
+}
@@ -0,0 +1,55 @@
+package scaler
+
+import (
+	"math"
+	"testing"
+)
+
+// seasonalSeries returns a load with a daily cycle, a quieter weekend and a steady growth
+func seasonalSeries(i int) float64 {
+	daily := 20 * math.Sin(2*math.Pi*float64(i%holtWintersDailySlots)/float64(holtWintersDailySlots))
+	weekly := 0.0
+	if i%holtWintersWeeklySlots >= 5*holtWintersDailySlots {
+		weekly = -15
+	}
+	return 100 + 0.01*float64(i) + daily + weekly
+}
+
+func TestHoltWintersForecast(t *testing.T) {
+	samples := 3 * holtWintersWeeklySlots
+	series := make([]float64, samples)
+	for i := range series {
+		series[i] = seasonalSeries(i)
+	}
+
+	model, err := fitHoltWinters(series, 0.1, 0.01, 0.1, 0.1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		steps int
+	}{
+		{"next period", 1},
+		{"one hour", 12},
+		{"half a day", holtWintersDailySlots / 2},
+		{"one day", holtWintersDailySlots},
+		{"into the weekend", 5*holtWintersDailySlots + 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := seasonalSeries(samples + tt.steps - 1)
+			if got := model.forecast(tt.steps); math.Abs(got-expected) > 1 {
+				t.Errorf("forecast(%d) = %.2f, expected %.2f", tt.steps, got, expected)
+			}
+		})
+	}
+}
+
+func TestFitHoltWintersRequiresTwoWeeks(t *testing.T) {
+	if _, err := fitHoltWinters(make([]float64, 2*holtWintersWeeklySlots-1), 0.1, 0.01, 0.1, 0.1); err == nil {
+		t.Error("expected an error for less than two weeks of data points")
+	}
+}
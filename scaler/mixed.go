@@ -0,0 +1,85 @@
+package scaler
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"predictive-rds-scaler/metrics"
+	"predictive-rds-scaler/types"
+	"time"
+)
+
+// the load right after the burst duration is averaged over this window, long enough to smooth out
+// single data points but short enough to leave the burst out
+const plateauWindow = 10 * time.Minute
+
+// readerPlan is a number of readers to create with the given instance classes, none to use the
+// reader instance classes
+type readerPlan struct {
+	count           uint
+	instanceClasses []string
+}
+
+func isServerlessInstance(instance *rds.DBInstance) bool {
+	return aws.StringValue(instance.DBInstanceClass) == metrics.ServerlessInstanceClass
+}
+
+// readerPlans splits the readers to add into provisioned and Serverless v2 readers. In the mixed
+// scaling mode capacity that is still needed after the burst duration goes provisioned, short spikes
+// are covered by serverless readers.
+func (s *Scaler) readerPlans(now time.Time, clusterStatus *types.ClusterStatus, numInstances uint, minInstances uint, maxInstances uint) []readerPlan {
+	if s.config.ScalingMode != ScalingModeMixed {
+		return []readerPlan{{count: numInstances}}
+	}
+
+	plateauSize := s.plateauSize(now, clusterStatus, minInstances, maxInstances)
+	provisionedCount := provisionedReaderCount(clusterStatus, s.config.ExcludeWriter)
+
+	var provisioned uint
+	if plateauSize > provisionedCount {
+		provisioned = min(plateauSize-provisionedCount, numInstances)
+	}
+
+	s.logger.Info().
+		Uint("Provisioned", provisioned).
+		Uint("Serverless", numInstances-provisioned).
+		Msg("Splitting scale out into provisioned and serverless readers")
+
+	return []readerPlan{
+		{count: provisioned},
+		{count: numInstances - provisioned, instanceClasses: []string{metrics.ServerlessInstanceClass}},
+	}
+}
+
+// preferServerlessScaleIn reports whether serverless readers are removed first, which is the case
+// as long as the provisioned readers are still needed after the burst duration
+func (s *Scaler) preferServerlessScaleIn(now time.Time, clusterStatus *types.ClusterStatus, minInstances uint, maxInstances uint) bool {
+	if s.config.ScalingMode != ScalingModeMixed {
+		return false
+	}
+	return s.plateauSize(now, clusterStatus, minInstances, maxInstances) >= provisionedReaderCount(clusterStatus, s.config.ExcludeWriter)
+}
+
+// plateauSize predicts the size the cluster needs once the burst duration has passed, from the load
+// after it only
+func (s *Scaler) plateauSize(now time.Time, clusterStatus *types.ClusterStatus, minInstances uint, maxInstances uint) uint {
+	plateauStatus, err := predictAt(s.predictor, now, s.config.PlanAheadTime+s.config.BurstDuration, plateauWindow)
+	if err != nil {
+		// without a long-term forecast all additional capacity is treated as a spike
+		s.logger.Error().Err(err).Msg("Error predicting cluster status after the burst duration")
+		return 0
+	}
+	s.estimateCapacity(plateauStatus, clusterStatus)
+	return max(minInstances, min(maxInstances, plateauStatus.OptimalSize))
+}
+
+// provisionedReaderCount counts the available instances that aren't Serverless v2 readers
+func provisionedReaderCount(clusterStatus *types.ClusterStatus, excludeWriter bool) uint {
+	var count uint
+	for _, instance := range clusterStatus.Instances {
+		if instance.Status != "available" || instance.InstanceClass == metrics.ServerlessInstanceClass || (instance.IsWriter && excludeWriter) {
+			continue
+		}
+		count++
+	}
+	return count
+}
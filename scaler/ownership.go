@@ -0,0 +1,55 @@
+package scaler
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"strings"
+)
+
+// marks the readers created by the scaler with the cluster that manages them, the scaler never
+// deletes or modifies an instance without it
+const ownerTagKey = "predictive-rds-scaler:owner"
+
+// ownerTags returns the tags new readers are created with
+func (s *Scaler) ownerTags() []*rds.Tag {
+	return []*rds.Tag{
+		{
+			Key:   aws.String(ownerTagKey),
+			Value: aws.String(s.config.RdsClusterName),
+		},
+	}
+}
+
+// isOwnedInstance reports whether the instance was created by this scaler, by its name and the
+// ownership tag applied at creation
+func (s *Scaler) isOwnedInstance(instance *rds.DBInstance) bool {
+	if !strings.HasPrefix(aws.StringValue(instance.DBInstanceIdentifier), s.config.InstanceNamePrefix) {
+		return false
+	}
+
+	for _, tag := range instance.TagList {
+		if aws.StringValue(tag.Key) == ownerTagKey {
+			return aws.StringValue(tag.Value) == s.config.RdsClusterName
+		}
+	}
+	return false
+}
+
+// warnUntaggedInstances logs readers that carry the name prefix but not the ownership tag, like
+// readers created by versions before the tag, once. They are never scaled in until they are tagged.
+func (s *Scaler) warnUntaggedInstances(readerInstances []*rds.DBInstance) {
+	for _, instance := range readerInstances {
+		identifier := aws.StringValue(instance.DBInstanceIdentifier)
+		if s.untaggedInstances[identifier] || s.isOwnedInstance(instance) || isAdoptedInstance(instance) ||
+			!strings.HasPrefix(identifier, s.config.InstanceNamePrefix) {
+			continue
+		}
+
+		s.untaggedInstances[identifier] = true
+		s.logger.Warn().
+			Str("InstanceIdentifier", identifier).
+			Str("TagKey", ownerTagKey).
+			Str("TagValue", s.config.RdsClusterName).
+			Msg("Reader has the instance name prefix but no ownership tag, it is never scaled in until tagged")
+	}
+}
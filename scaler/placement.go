@@ -0,0 +1,161 @@
+package scaler
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"predictive-rds-scaler/types"
+	"sort"
+	"time"
+)
+
+// placement is a combination of instance class and AZ a reader can be created with, an empty AZ
+// leaves the choice to AWS
+type placement struct {
+	instanceClass    string
+	availabilityZone string
+}
+
+// availabilityZonesForPlacement returns the AZs readers are balanced across, the configured ones or
+// otherwise those of the cluster
+func (s *Scaler) availabilityZonesForPlacement() ([]string, error) {
+	if len(s.availabilityZones) > 0 {
+		return s.availabilityZones, nil
+	}
+
+	clusterOutput, err := s.rdsClient.DescribeDBClusters(&rds.DescribeDBClustersInput{
+		DBClusterIdentifier: aws.String(s.config.RdsClusterName),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(clusterOutput.DBClusters) == 0 {
+		return nil, fmt.Errorf("aurora cluster not found: %s", s.config.RdsClusterName)
+	}
+	return aws.StringValueSlice(clusterOutput.DBClusters[0].AvailabilityZones), nil
+}
+
+// readersPerAvailabilityZone counts the readers in each AZ, readers that were just created may not
+// report their AZ yet, pending maps them to the AZ they were placed in
+func readersPerAvailabilityZone(readerInstances []*rds.DBInstance, pending map[string]string) map[string]int {
+	counts := make(map[string]int)
+	seen := make(map[string]bool, len(readerInstances))
+
+	for _, instance := range readerInstances {
+		identifier := aws.StringValue(instance.DBInstanceIdentifier)
+		seen[identifier] = true
+
+		availabilityZone := aws.StringValue(instance.AvailabilityZone)
+		if availabilityZone == "" {
+			availabilityZone = pending[identifier]
+		}
+		counts[availabilityZone]++
+	}
+
+	for identifier, availabilityZone := range pending {
+		if !seen[identifier] {
+			counts[availabilityZone]++
+		}
+	}
+	return counts
+}
+
+// availabilityZonesByReaders orders the AZs by their number of readers, ties keep the configured
+// order. Without AZs the placement is left to AWS.
+func availabilityZonesByReaders(availabilityZones []string, readerInstances []*rds.DBInstance, pending map[string]string) []string {
+	if len(availabilityZones) == 0 {
+		return []string{""}
+	}
+
+	counts := readersPerAvailabilityZone(readerInstances, pending)
+
+	ordered := append([]string(nil), availabilityZones...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return counts[ordered[i]] < counts[ordered[j]]
+	})
+	return ordered
+}
+
+// placementCandidates returns the fallback chain of a new reader, each instance class in order of
+// preference in the AZs with the fewest readers first
+func placementCandidates(instanceClasses []string, availabilityZones []string) []placement {
+	var candidates []placement
+	for _, instanceClass := range instanceClasses {
+		for _, availabilityZone := range availabilityZones {
+			candidates = append(candidates, placement{instanceClass: instanceClass, availabilityZone: availabilityZone})
+		}
+	}
+	return candidates
+}
+
+// isPlacementUnavailable reports whether the placement recently ran out of capacity
+func (s *Scaler) isPlacementUnavailable(now time.Time, candidate placement) bool {
+	until, ok := s.unavailablePlacements[candidate]
+	return ok && now.Before(until)
+}
+
+// markPlacementUnavailable skips the placement for new readers until the capacity blacklist
+// duration has passed
+func (s *Scaler) markPlacementUnavailable(now time.Time, candidate placement) {
+	s.unavailablePlacements[candidate] = now.Add(s.config.CapacityBlacklistDuration)
+
+	var unavailable []types.UnavailablePlacement
+	for p, until := range s.unavailablePlacements {
+		if !now.Before(until) {
+			delete(s.unavailablePlacements, p)
+			continue
+		}
+		unavailable = append(unavailable, types.UnavailablePlacement{
+			InstanceClass:    p.instanceClass,
+			AvailabilityZone: p.availabilityZone,
+			Until:            until,
+		})
+	}
+	sort.Slice(unavailable, func(i, j int) bool {
+		return unavailable[i].Until.Before(unavailable[j].Until)
+	})
+
+	s.scalerStatus.UnavailablePlacements = unavailable
+	s.submitBroadcast(&types.Broadcast{MessageType: "scalerStatus", Data: s.scalerStatus})
+}
+
+// chooseScaleInVictim returns the index of the candidate to remove, one of the preferred type in
+// the AZ with the most readers, so the remaining readers stay balanced. All readers count towards
+// the balance. It returns -1 without candidates.
+func chooseScaleInVictim(readerInstances []*rds.DBInstance, isCandidate func(*rds.DBInstance) bool, preferServerless bool) int {
+	counts := readersPerAvailabilityZone(readerInstances, nil)
+
+	hasPreferred := false
+	for _, instance := range readerInstances {
+		if isCandidate(instance) && isServerlessInstance(instance) == preferServerless {
+			hasPreferred = true
+		}
+	}
+
+	victim := -1
+	for i, instance := range readerInstances {
+		if !isCandidate(instance) || (hasPreferred && isServerlessInstance(instance) != preferServerless) {
+			continue
+		}
+		if victim < 0 || counts[aws.StringValue(instance.AvailabilityZone)] > counts[aws.StringValue(readerInstances[victim].AvailabilityZone)] {
+			victim = i
+		}
+	}
+	return victim
+}
+
+// reportPlacement shows the instance class and AZ a new reader was created with, and whether it
+// fell back from the preferred ones
+func (s *Scaler) reportPlacement(readerName string, used placement, preferred placement) {
+	s.scalerStatus.LastPlacement = &types.ReaderPlacement{
+		Timestamp:                 time.Now().In(time.UTC),
+		InstanceIdentifier:        readerName,
+		InstanceClass:             used.instanceClass,
+		AvailabilityZone:          used.availabilityZone,
+		PreferredInstanceClass:    preferred.instanceClass,
+		PreferredAvailabilityZone: preferred.availabilityZone,
+		IsFallback:                used != preferred,
+	}
+	s.submitBroadcast(&types.Broadcast{MessageType: "scalerStatus", Data: s.scalerStatus})
+}
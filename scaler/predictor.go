@@ -0,0 +1,97 @@
+package scaler
+
+import (
+	"fmt"
+	"math"
+	"predictive-rds-scaler/metrics"
+	"predictive-rds-scaler/types"
+	"time"
+)
+
+const (
+	PredictorWeekAgo     = "weekAgo"
+	PredictorSeasonal    = "seasonal"
+	PredictorHoltWinters = "holtWinters"
+	PredictorTrend       = "trend"
+	PredictorEnsemble    = "ensemble"
+)
+
+// Predictor forecasts the status of the cluster at now + horizon.
+type Predictor interface {
+	Name() string
+	Predict(now time.Time, horizon time.Duration) (*types.ClusterStatus, error)
+}
+
+// windowPredictor forecasts the average status of the cluster over a window, which may also start
+// later than now. Its Predict averages over the whole horizon.
+type windowPredictor interface {
+	PredictWindow(now time.Time, offset time.Duration, window time.Duration) (*types.ClusterStatus, error)
+}
+
+// predictAt forecasts the status of the cluster around now + offset only, window predictors are
+// asked for the short window starting there
+func predictAt(predictor Predictor, now time.Time, offset time.Duration, window time.Duration) (*types.ClusterStatus, error) {
+	if p, ok := predictor.(windowPredictor); ok {
+		return p.PredictWindow(now, offset, window)
+	}
+	// the other predictors forecast the status at a point in time
+	return predictor.Predict(now, offset)
+}
+
+func newPredictor(conf *types.Config, cloudwatchMetrics *metrics.Metrics, calendar *Calendar, accuracy *accuracyTracker) (Predictor, error) {
+	return newPredictorByName(conf.Predictor, conf, cloudwatchMetrics, calendar, accuracy)
+}
+
+func newPredictorByName(name string, conf *types.Config, cloudwatchMetrics *metrics.Metrics, calendar *Calendar, accuracy *accuracyTracker) (Predictor, error) {
+	switch name {
+	case PredictorWeekAgo, "":
+		return &weekAgoPredictor{metrics: cloudwatchMetrics, calendar: calendar}, nil
+	case PredictorSeasonal:
+		return newSeasonalPredictor(conf, cloudwatchMetrics, calendar)
+	case PredictorHoltWinters:
+		return newHoltWintersPredictor(conf, cloudwatchMetrics, calendar)
+	case PredictorTrend:
+		return newTrendPredictor(conf, cloudwatchMetrics)
+	case PredictorEnsemble:
+		return newEnsemblePredictor(conf, cloudwatchMetrics, calendar, accuracy)
+	default:
+		return nil, fmt.Errorf("unknown predictor: %s", name)
+	}
+}
+
+// weekAgoPredictor assumes the cluster behaves exactly as it did at the same time last week.
+type weekAgoPredictor struct {
+	metrics  *metrics.Metrics
+	calendar *Calendar
+}
+
+func (p *weekAgoPredictor) Name() string {
+	return PredictorWeekAgo
+}
+
+func (p *weekAgoPredictor) Predict(now time.Time, horizon time.Duration) (*types.ClusterStatus, error) {
+	return p.PredictWindow(now, 0, horizon)
+}
+
+func (p *weekAgoPredictor) PredictWindow(now time.Time, offset time.Duration, window time.Duration) (*types.ClusterStatus, error) {
+	historic, ok := p.calendar.weeksAgo(now.Add(offset), 1)
+	if !ok {
+		return nil, fmt.Errorf("no usable history within the last %d weeks", calendarMaxLookbackWeeks)
+	}
+	return p.metrics.GetHistoricClusterStatusAt(historic, window)
+}
+
+// newPredictedStatus builds a cluster status from a predicted total load, i.e. the sum of the CPU
+// utilization of all instances, distributed across the predicted number of readers.
+func newPredictedStatus(conf *types.Config, cloudwatchMetrics *metrics.Metrics, timestamp time.Time, load float64, readerCount float64) *types.ClusterStatus {
+	readers := uint(math.Max(1, math.Round(readerCount)))
+	averageCPUUtilization := math.Max(0, load) / float64(readers)
+
+	return &types.ClusterStatus{
+		Identifier:            conf.RdsClusterName,
+		Timestamp:             timestamp,
+		AverageCPUUtilization: averageCPUUtilization,
+		CurrentActiveReaders:  readers,
+		OptimalSize:           cloudwatchMetrics.CalculateOptimalClusterSize(averageCPUUtilization, readers, conf.MinInstances),
+	}
+}
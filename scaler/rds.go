@@ -3,9 +3,11 @@ package scaler
 import (
 	"fmt"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/rds"
 	"math/rand"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -43,6 +45,13 @@ const (
 	StatusUpgrading                                    = 0x40000000         // 1073741824
 )
 
+// returned by CreateDBInstance for an instance class the engine version doesn't support, among other
+// invalid parameters. The message of an unsupported class names the combination of class and engine.
+const (
+	errCodeInvalidParameterCombination       = "InvalidParameterCombination"
+	errMessageUnsupportedInstanceClassMarker = "DBInstanceClass="
+)
+
 func (s *Scaler) getWriterInstance() (*rds.DBInstance, error) {
 	describeInput := &rds.DescribeDBClustersInput{
 		DBClusterIdentifier: aws.String(s.config.RdsClusterName),
@@ -80,10 +89,58 @@ func (s *Scaler) getWriterInstance() (*rds.DBInstance, error) {
 	return nil, fmt.Errorf("writer instance not found in cluster: %s", s.config.RdsClusterName)
 }
 
-func (s *Scaler) createReaderInstance(readerName string, writerInstance *rds.DBInstance) (*rds.CreateDBInstanceOutput, error) {
+// createReaderInstanceWithFallback creates the reader with the first placement that has capacity
+// and supports the instance class, and returns that placement. Placements without capacity are
+// skipped for a while, so later scale-outs don't retry them.
+func (s *Scaler) createReaderInstanceWithFallback(readerName string, writerInstance *rds.DBInstance, candidates []placement) (placement, error) {
+	unsupportedClasses := make(map[string]bool)
+
+	var err error
+	for i, candidate := range candidates {
+		if unsupportedClasses[candidate.instanceClass] || s.isPlacementUnavailable(time.Now().In(time.UTC), candidate) {
+			continue
+		}
+
+		_, err = s.createReaderInstance(readerName, writerInstance, candidate.instanceClass, candidate.availabilityZone)
+		if err == nil {
+			if i > 0 {
+				s.logger.Warn().
+					Str("InstanceClass", candidate.instanceClass).
+					Str("AvailabilityZone", candidate.availabilityZone).
+					Str("PreferredInstanceClass", candidates[0].instanceClass).
+					Str("PreferredAvailabilityZone", candidates[0].availabilityZone).
+					Msg("Reader created with a fallback placement")
+			}
+			s.reportPlacement(readerName, candidate, candidates[0])
+			return candidate, nil
+		}
+
+		if !isInstanceClassUnavailable(err) {
+			return placement{}, err
+		}
+
+		if isInsufficientCapacity(err) {
+			s.markPlacementUnavailable(time.Now().In(time.UTC), candidate)
+		} else {
+			unsupportedClasses[candidate.instanceClass] = true
+		}
+		s.logger.Warn().
+			Err(err).
+			Str("InstanceClass", candidate.instanceClass).
+			Str("AvailabilityZone", candidate.availabilityZone).
+			Msg("Placement unavailable, trying the next fallback")
+	}
+
+	if err == nil {
+		err = fmt.Errorf("all instance classes and availability zones are temporarily out of capacity")
+	}
+	return placement{}, err
+}
+
+func (s *Scaler) createReaderInstance(readerName string, writerInstance *rds.DBInstance, instanceClass string, availabilityZone string) (*rds.CreateDBInstanceOutput, error) {
 	// Use the writer instance's configuration as a template for the new reader instance
 	readerDBInstance := &rds.CreateDBInstanceInput{
-		DBInstanceClass:         writerInstance.DBInstanceClass,
+		DBInstanceClass:         aws.String(instanceClass),
 		Engine:                  writerInstance.Engine,
 		DBClusterIdentifier:     aws.String(s.config.RdsClusterName),
 		DBInstanceIdentifier:    aws.String(readerName),
@@ -93,6 +150,13 @@ func (s *Scaler) createReaderInstance(readerName string, writerInstance *rds.DBI
 		AutoMinorVersionUpgrade: writerInstance.AutoMinorVersionUpgrade,
 		DBParameterGroupName:    writerInstance.DBParameterGroups[0].DBParameterGroupName,
 		CACertificateIdentifier: writerInstance.CACertificateIdentifier,
+		PromotionTier:           aws.Int64(s.config.PromotionTier),
+		Tags:                    s.ownerTags(),
+	}
+
+	// an empty AZ leaves the placement to AWS
+	if availabilityZone != "" {
+		readerDBInstance.AvailabilityZone = aws.String(availabilityZone)
 	}
 
 	// Perform the scaling operation to add a reader to the cluster
@@ -269,6 +333,29 @@ func (s *Scaler) getClusterTags(clusterArn string) (map[string]string, error) {
 	return tags, nil
 }
 
+// isInstanceClassUnavailable reports whether the creation failed because of the instance class,
+// either for a lack of capacity or because the engine doesn't support it. Other invalid parameters
+// fail the creation.
+func isInstanceClassUnavailable(err error) bool {
+	if awsErr, ok := err.(awserr.Error); ok {
+		switch awsErr.Code() {
+		case rds.ErrCodeInsufficientDBInstanceCapacityFault:
+			return true
+		case errCodeInvalidParameterCombination:
+			return strings.Contains(awsErr.Message(), errMessageUnsupportedInstanceClassMarker)
+		}
+	}
+	return false
+}
+
+// isInsufficientCapacity reports whether AWS lacks capacity for the instance class in the AZ
+func isInsufficientCapacity(err error) bool {
+	if awsErr, ok := err.(awserr.Error); ok {
+		return awsErr.Code() == rds.ErrCodeInsufficientDBInstanceCapacityFault
+	}
+	return false
+}
+
 func isDeletableStatus(status string) bool {
 	invalidStatus := []string{"deleting", "modifying", "maintenance", "rebooting"}
 	return !containsString(invalidStatus, status)
@@ -0,0 +1,217 @@
+package scaler
+
+import (
+	"fmt"
+	"github.com/robfig/cron/v3"
+	"gopkg.in/yaml.v3"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// ScheduleRule sets the instance limits while it is active. A rule is either a cron expression
+// starting a window of the given duration, or a daily time window on the given days of the week,
+// e.g. days "Mon-Fri", start "08:00" and end "18:00". Times are evaluated in the rule's timezone.
+type ScheduleRule struct {
+	Name         string        `yaml:"name"`
+	Cron         string        `yaml:"cron"`
+	Duration     time.Duration `yaml:"duration"`
+	Days         string        `yaml:"days"`
+	Start        string        `yaml:"start"`
+	End          string        `yaml:"end"`
+	Timezone     string        `yaml:"timezone"`
+	MinInstances *uint         `yaml:"min_instances"`
+	MaxInstances *uint         `yaml:"max_instances"`
+
+	schedule cron.Schedule
+	weekdays map[time.Weekday]bool
+	start    time.Duration
+	end      time.Duration
+	location *time.Location
+}
+
+// LoadSchedule reads the schedule rules from a YAML file
+func LoadSchedule(path string) ([]*ScheduleRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []*ScheduleRule
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse schedule %s: %v", path, err)
+	}
+
+	for _, rule := range rules {
+		if err := rule.init(); err != nil {
+			return nil, err
+		}
+	}
+	return rules, nil
+}
+
+// boostHourRules translates the legacy boost hours, which raise the minimum by one during the
+// given UTC hours, into schedule rules
+func boostHourRules(boostHours []int, minInstances uint) ([]*ScheduleRule, error) {
+	boostedMinInstances := minInstances + 1
+
+	rules := make([]*ScheduleRule, 0, len(boostHours))
+	for _, hour := range boostHours {
+		rule := &ScheduleRule{
+			Name:         fmt.Sprintf("boost-hour-%d", hour),
+			Cron:         fmt.Sprintf("0 %d * * *", hour),
+			Duration:     time.Hour,
+			Timezone:     "UTC",
+			MinInstances: &boostedMinInstances,
+		}
+		if err := rule.init(); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (r *ScheduleRule) init() error {
+	var err error
+
+	r.location, err = time.LoadLocation(r.Timezone)
+	if err != nil {
+		return fmt.Errorf("invalid timezone in schedule rule %s: %v", r.Name, err)
+	}
+
+	if r.MinInstances == nil && r.MaxInstances == nil {
+		return fmt.Errorf("schedule rule %s requires min_instances or max_instances", r.Name)
+	}
+
+	if r.Cron != "" {
+		if r.Duration <= 0 {
+			return fmt.Errorf("schedule rule %s requires a duration for its cron expression", r.Name)
+		}
+		r.schedule, err = cron.ParseStandard(r.Cron)
+		if err != nil {
+			return fmt.Errorf("invalid cron expression in schedule rule %s: %v", r.Name, err)
+		}
+		return nil
+	}
+
+	if r.start, err = parseTimeOfDay(r.Start); err != nil {
+		return fmt.Errorf("invalid start in schedule rule %s: %v", r.Name, err)
+	}
+	if r.end, err = parseTimeOfDay(r.End); err != nil {
+		return fmt.Errorf("invalid end in schedule rule %s: %v", r.Name, err)
+	}
+	if r.weekdays, err = parseWeekdays(r.Days); err != nil {
+		return fmt.Errorf("invalid days in schedule rule %s: %v", r.Name, err)
+	}
+	return nil
+}
+
+// isActive reports whether the rule applies at the given time
+func (r *ScheduleRule) isActive(now time.Time) bool {
+	local := now.In(r.location)
+
+	if r.schedule != nil {
+		// active if the schedule fired within the last duration
+		return !r.schedule.Next(local.Add(-1 * r.Duration)).After(local)
+	}
+
+	// the wall-clock time, the elapsed time since midnight is off by an hour on DST switch days
+	timeOfDay := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute + time.Duration(local.Second())*time.Second
+
+	if r.start <= r.end {
+		return r.weekdays[local.Weekday()] && timeOfDay >= r.start && timeOfDay < r.end
+	}
+
+	// the window passes midnight, it belongs to the day it started on
+	previousDay := (local.Weekday() + 6) % 7
+	return (r.weekdays[local.Weekday()] && timeOfDay >= r.start) ||
+		(r.weekdays[previousDay] && timeOfDay < r.end)
+}
+
+// scheduledInstanceLimits applies the active rules to the given limits. The highest minimum of the
+// active rules wins, and so does the highest maximum.
+func scheduledInstanceLimits(rules []*ScheduleRule, now time.Time, minInstances, maxInstances uint) (uint, uint) {
+	var scheduledMax *uint
+
+	for _, rule := range rules {
+		if !rule.isActive(now) {
+			continue
+		}
+
+		if rule.MinInstances != nil {
+			minInstances = max(minInstances, *rule.MinInstances)
+		}
+		if rule.MaxInstances != nil && (scheduledMax == nil || *rule.MaxInstances > *scheduledMax) {
+			scheduledMax = rule.MaxInstances
+		}
+	}
+
+	if scheduledMax != nil {
+		maxInstances = *scheduledMax
+	}
+	return minInstances, maxInstances
+}
+
+func parseTimeOfDay(value string) (time.Duration, error) {
+	hoursStr, minutesStr, found := strings.Cut(value, ":")
+	if !found {
+		return 0, fmt.Errorf("expected HH:MM, got %q", value)
+	}
+
+	hours, err := strconv.Atoi(hoursStr)
+	if err != nil || hours < 0 || hours > 24 {
+		return 0, fmt.Errorf("invalid hour: %s", hoursStr)
+	}
+	minutes, err := strconv.Atoi(minutesStr)
+	if err != nil || minutes < 0 || minutes > 59 {
+		return 0, fmt.Errorf("invalid minute: %s", minutesStr)
+	}
+
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute, nil
+}
+
+// parseWeekdays parses lists like "Mon-Fri" or "Sat,Sun", an empty list means every day
+func parseWeekdays(value string) (map[time.Weekday]bool, error) {
+	days := make(map[time.Weekday]bool)
+	if value == "" {
+		for _, day := range weekdays {
+			days[day] = true
+		}
+		return days, nil
+	}
+
+	for _, item := range splitAndTrimStrings(value, ",") {
+		fromStr, toStr, isRange := strings.Cut(item, "-")
+		from, ok := weekdays[strings.ToLower(strings.TrimSpace(fromStr))]
+		if !ok {
+			return nil, fmt.Errorf("unknown day: %s", fromStr)
+		}
+
+		to := from
+		if isRange {
+			if to, ok = weekdays[strings.ToLower(strings.TrimSpace(toStr))]; !ok {
+				return nil, fmt.Errorf("unknown day: %s", toStr)
+			}
+		}
+
+		for day := from; ; day = (day + 1) % 7 {
+			days[day] = true
+			if day == to {
+				break
+			}
+		}
+	}
+	return days, nil
+}
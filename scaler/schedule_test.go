@@ -0,0 +1,93 @@
+package scaler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleRuleIsActive(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	minInstances := uint(2)
+
+	businessHours := &ScheduleRule{Name: "business-hours", Days: "Mon-Fri", Start: "08:00", End: "18:00", Timezone: "Europe/Berlin", MinInstances: &minInstances}
+	overnight := &ScheduleRule{Name: "overnight", Days: "Fri", Start: "22:00", End: "06:00", Timezone: "Europe/Berlin", MinInstances: &minInstances}
+	everyDay := &ScheduleRule{Name: "every-day", Start: "08:00", End: "18:00", Timezone: "Europe/Berlin", MinInstances: &minInstances}
+	for _, rule := range []*ScheduleRule{businessHours, overnight, everyDay} {
+		if err := rule.init(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		rule     *ScheduleRule
+		now      time.Time
+		expected bool
+	}{
+		{"window start", businessHours, time.Date(2026, 10, 12, 8, 0, 0, 0, berlin), true},
+		{"before window start", businessHours, time.Date(2026, 10, 12, 7, 59, 59, 0, berlin), false},
+		{"last second of window", businessHours, time.Date(2026, 10, 12, 17, 59, 59, 0, berlin), true},
+		{"window end", businessHours, time.Date(2026, 10, 12, 18, 0, 0, 0, berlin), false},
+		{"excluded weekday", businessHours, time.Date(2026, 10, 17, 12, 0, 0, 0, berlin), false},
+		{"evaluated in rule timezone", businessHours, time.Date(2026, 10, 12, 6, 30, 0, 0, time.UTC), true},
+		{"overnight start day", overnight, time.Date(2026, 10, 16, 23, 0, 0, 0, berlin), true},
+		{"overnight after midnight", overnight, time.Date(2026, 10, 17, 5, 59, 0, 0, berlin), true},
+		{"overnight window end", overnight, time.Date(2026, 10, 17, 6, 0, 0, 0, berlin), false},
+		{"overnight on the following evening", overnight, time.Date(2026, 10, 17, 23, 0, 0, 0, berlin), false},
+		{"overnight from excluded day", overnight, time.Date(2026, 10, 16, 5, 0, 0, 0, berlin), false},
+		{"dst switch day morning", everyDay, time.Date(2026, 3, 29, 8, 30, 0, 0, berlin), true},
+		{"dst switch day evening", everyDay, time.Date(2026, 3, 29, 18, 30, 0, 0, berlin), false},
+		{"dst end day evening", everyDay, time.Date(2026, 10, 25, 17, 30, 0, 0, berlin), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.isActive(tt.now); got != tt.expected {
+				t.Errorf("isActive(%s) = %v, expected %v", tt.now, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseWeekdays(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected []time.Weekday
+		wantErr  bool
+	}{
+		{value: "", expected: []time.Weekday{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday}},
+		{value: "Mon-Fri", expected: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}},
+		{value: "Sat, sun", expected: []time.Weekday{time.Saturday, time.Sunday}},
+		{value: "Fri-Mon", expected: []time.Weekday{time.Friday, time.Saturday, time.Sunday, time.Monday}},
+		{value: "Wed", expected: []time.Weekday{time.Wednesday}},
+		{value: "Funday", wantErr: true},
+		{value: "Mon-Someday", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			days, err := parseWeekdays(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %v", days)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(days) != len(tt.expected) {
+				t.Errorf("got %d days, expected %d", len(days), len(tt.expected))
+			}
+			for _, day := range tt.expected {
+				if !days[day] {
+					t.Errorf("expected %s to be included", day)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package scaler
+
+import (
+	"fmt"
+	"predictive-rds-scaler/metrics"
+	"predictive-rds-scaler/types"
+	"strconv"
+	"time"
+)
+
+const (
+	AggregationWeighted = "weighted"
+	AggregationMedian   = "median"
+)
+
+// seasonalPredictor combines the same weekday/time slot of the last N weeks, so that a single
+// unusual week does not dictate the size of the cluster on its own.
+type seasonalPredictor struct {
+	config      *types.Config
+	metrics     *metrics.Metrics
+	calendar    *Calendar
+	weeks       int
+	weights     []float64
+	aggregation string
+}
+
+func newSeasonalPredictor(conf *types.Config, cloudwatchMetrics *metrics.Metrics, calendar *Calendar) (*seasonalPredictor, error) {
+	if conf.SeasonalWeeks == 0 {
+		return nil, fmt.Errorf("seasonal predictor requires at least one week of history")
+	}
+
+	weights, err := parseSeasonalWeights(conf.SeasonalWeights, int(conf.SeasonalWeeks))
+	if err != nil {
+		return nil, err
+	}
+
+	switch conf.SeasonalAggregation {
+	case AggregationWeighted, AggregationMedian:
+	default:
+		return nil, fmt.Errorf("unknown seasonal aggregation: %s", conf.SeasonalAggregation)
+	}
+
+	return &seasonalPredictor{
+		config:      conf,
+		metrics:     cloudwatchMetrics,
+		calendar:    calendar,
+		weeks:       int(conf.SeasonalWeeks),
+		weights:     weights,
+		aggregation: conf.SeasonalAggregation,
+	}, nil
+}
+
+func (p *seasonalPredictor) Name() string {
+	return PredictorSeasonal
+}
+
+func (p *seasonalPredictor) Predict(now time.Time, horizon time.Duration) (*types.ClusterStatus, error) {
+	return p.PredictWindow(now, 0, horizon)
+}
+
+func (p *seasonalPredictor) PredictWindow(now time.Time, offset time.Duration, window time.Duration) (*types.ClusterStatus, error) {
+	var loads, readers, weights []float64
+
+	for week := 1; week <= p.weeks; week++ {
+		// holidays and other special days would skew the combination, leave them out
+		historic, ok := p.calendar.historicTime(p.calendar.sameTimeWeeksAgo(now.Add(offset), week))
+		if !ok {
+			continue
+		}
+
+		status, err := p.metrics.GetHistoricClusterStatusAt(historic, window)
+		if err != nil {
+			// a missing week only reduces the sample, the remaining weeks still make a prediction
+			continue
+		}
+
+		loads = append(loads, status.AverageCPUUtilization*float64(status.CurrentActiveReaders))
+		readers = append(readers, float64(status.CurrentActiveReaders))
+		weights = append(weights, p.weights[week-1])
+	}
+
+	if len(loads) == 0 {
+		return nil, fmt.Errorf("no historic data available for the last %d weeks", p.weeks)
+	}
+
+	var load, readerCount float64
+	if p.aggregation == AggregationMedian {
+		load = median(loads)
+		readerCount = median(readers)
+	} else {
+		load = weightedMean(loads, weights)
+		readerCount = weightedMean(readers, weights)
+	}
+
+	return newPredictedStatus(p.config, p.metrics, now.Add(offset+window), load, readerCount), nil
+}
+
+func parseSeasonalWeights(weightsStr string, weeks int) ([]float64, error) {
+	if weightsStr == "" {
+		weights := make([]float64, weeks)
+		for i := range weights {
+			weights[i] = 1
+		}
+		return weights, nil
+	}
+
+	weightStrs := splitAndTrimStrings(weightsStr, ",")
+	if len(weightStrs) != weeks {
+		return nil, fmt.Errorf("expected %d seasonal weights, got %d", weeks, len(weightStrs))
+	}
+
+	weights := make([]float64, 0, weeks)
+	for _, weightStr := range weightStrs {
+		weight, err := strconv.ParseFloat(weightStr, 64)
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("invalid seasonal weight: %s", weightStr)
+		}
+		weights = append(weights, weight)
+	}
+	return weights, nil
+}
@@ -0,0 +1,134 @@
+package scaler
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"math"
+	"predictive-rds-scaler/types"
+	"time"
+)
+
+const (
+	ScalingModeInstances  = "instances"
+	ScalingModeServerless = "serverless"
+	ScalingModeMixed      = "mixed"
+
+	// Serverless v2 capacity is set in steps of half an ACU
+	serverlessCapacityStep = 0.5
+)
+
+// requiredServerlessCapacity returns the minimum capacity in ACUs per instance that keeps the CPU
+// utilization of the given status at the target. The CPU utilization of Serverless v2 instances is
+// relative to the maximum capacity.
+func (s *Scaler) requiredServerlessCapacity(status *types.ClusterStatus, instanceCount uint, maxCapacity float64) float64 {
+	averageCPUUtilization := status.AverageCPUUtilization * float64(status.CurrentActiveReaders) / float64(max(1, instanceCount))
+	capacity := averageCPUUtilization * maxCapacity / s.config.TargetCpuUtil
+	return math.Ceil(capacity/serverlessCapacityStep) * serverlessCapacityStep
+}
+
+// scaleServerlessCapacity raises the minimum capacity of the cluster ahead of the predicted load, so
+// the instances don't have to scale up from a low floor, and lowers it again once the load is gone
+func (s *Scaler) scaleServerlessCapacity(now time.Time, clusterStatus *types.ClusterStatus, statuses ...*types.ClusterStatus) {
+	scalingConfiguration, err := s.getServerlessScalingConfiguration()
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Error getting serverless scaling configuration")
+		return
+	}
+
+	currentCapacity := aws.Float64Value(scalingConfiguration.MinCapacity)
+	maxCapacity := aws.Float64Value(scalingConfiguration.MaxCapacity)
+
+	capacity := s.config.ServerlessMinCapacity
+	for _, status := range append([]*types.ClusterStatus{clusterStatus}, statuses...) {
+		capacity = math.Max(capacity, s.requiredServerlessCapacity(status, clusterStatus.CurrentActiveReaders, maxCapacity))
+	}
+	capacity = math.Min(capacity, maxCapacity)
+
+	s.recordCapacityRecommendation(now, capacity)
+	s.scalerStatus.ServerlessMinCapacity = capacity
+	s.submitBroadcast(&types.Broadcast{MessageType: "scalerStatus", Data: s.scalerStatus})
+
+	s.logger.Info().
+		Float64("Current", currentCapacity).
+		Float64("Required", capacity).
+		Float64("MaxCapacity", maxCapacity).
+		Msg("Serverless minimum capacity")
+
+	if capacity == currentCapacity {
+		return
+	}
+
+	if s.scalerStatus.IsScaling {
+		s.logger.Info().Msg("Skipping serverless capacity change: Scaling operation already in progress")
+		return
+	}
+
+	startCooldown := s.startScaleOutCooldown
+	if capacity < currentCapacity {
+		if s.isScaleInCoolingDown(now) {
+			s.logger.Info().Time("Timeout", s.scalerStatus.ScaleInTimeout).Msg("Skipping serverless capacity decrease: Cooldown in progress")
+			return
+		}
+
+		// only lower the capacity as far as the required capacity stayed lower for the whole stabilization window
+		stabilizedCapacity, ok := s.stabilizedScaleInCapacity(now, currentCapacity)
+		if !ok {
+			s.logger.Info().
+				Dur("StabilizationWindow", s.config.ScaleInStabilizationWindow).
+				Msg("Skipping serverless capacity decrease: Required capacity not stable within the stabilization window")
+			return
+		}
+		capacity = stabilizedCapacity
+		startCooldown = s.startScaleInCooldown
+	} else if s.isScaleOutCoolingDown(now) {
+		s.logger.Info().Time("Timeout", s.scalerStatus.ScaleOutTimeout).Msg("Skipping serverless capacity increase: Cooldown in progress")
+		return
+	}
+
+	if s.config.DryRun {
+		s.planAction(types.PlannedAction{Action: ActionModifyServerlessMinCapacity, MinCapacity: capacity})
+		startCooldown(now)
+		return
+	}
+
+	err = s.modifyServerlessMinCapacity(capacity, maxCapacity)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Error changing serverless minimum capacity")
+		return
+	}
+
+	s.logger.Info().Float64("MinCapacity", capacity).Msg("Serverless minimum capacity changed")
+	startCooldown(now)
+}
+
+func (s *Scaler) getServerlessScalingConfiguration() (*rds.ServerlessV2ScalingConfigurationInfo, error) {
+	clusterOutput, err := s.rdsClient.DescribeDBClusters(&rds.DescribeDBClustersInput{
+		DBClusterIdentifier: aws.String(s.config.RdsClusterName),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(clusterOutput.DBClusters) == 0 {
+		return nil, fmt.Errorf("aurora cluster not found: %s", s.config.RdsClusterName)
+	}
+
+	scalingConfiguration := clusterOutput.DBClusters[0].ServerlessV2ScalingConfiguration
+	if scalingConfiguration == nil {
+		return nil, fmt.Errorf("cluster has no Serverless v2 scaling configuration: %s", s.config.RdsClusterName)
+	}
+	return scalingConfiguration, nil
+}
+
+func (s *Scaler) modifyServerlessMinCapacity(minCapacity float64, maxCapacity float64) error {
+	_, err := s.rdsClient.ModifyDBCluster(&rds.ModifyDBClusterInput{
+		DBClusterIdentifier: aws.String(s.config.RdsClusterName),
+		ServerlessV2ScalingConfiguration: &rds.ServerlessV2ScalingConfiguration{
+			MinCapacity: aws.Float64(minCapacity),
+			MaxCapacity: aws.Float64(maxCapacity),
+		},
+		ApplyImmediately: aws.Bool(true),
+	})
+	return err
+}
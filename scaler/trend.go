@@ -0,0 +1,117 @@
+package scaler
+
+import (
+	"fmt"
+	"predictive-rds-scaler/metrics"
+	"predictive-rds-scaler/types"
+	"time"
+)
+
+const (
+	TrendMethodLinear   = "linear"
+	TrendMethodTheilSen = "theilSen"
+
+	trendPeriod = time.Minute
+)
+
+// trendPredictor extrapolates the load of the last minutes, so a ramp-up that starts earlier than
+// it did in the past is picked up before the average CPU utilization crosses the target.
+type trendPredictor struct {
+	config  *types.Config
+	metrics *metrics.Metrics
+	window  time.Duration
+	method  string
+}
+
+func newTrendPredictor(conf *types.Config, cloudwatchMetrics *metrics.Metrics) (*trendPredictor, error) {
+	if conf.TrendWindow < 5*trendPeriod {
+		return nil, fmt.Errorf("trend window must be at least %s, got %s", 5*trendPeriod, conf.TrendWindow)
+	}
+
+	switch conf.TrendMethod {
+	case TrendMethodLinear, TrendMethodTheilSen:
+	default:
+		return nil, fmt.Errorf("unknown trend method: %s", conf.TrendMethod)
+	}
+
+	return &trendPredictor{
+		config:  conf,
+		metrics: cloudwatchMetrics,
+		window:  conf.TrendWindow,
+		method:  conf.TrendMethod,
+	}, nil
+}
+
+func (p *trendPredictor) Name() string {
+	return PredictorTrend
+}
+
+func (p *trendPredictor) Predict(now time.Time, horizon time.Duration) (*types.ClusterStatus, error) {
+	statusHistory, err := p.metrics.GetClusterStatus(now.Add(-1*p.window), now, trendPeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(statusHistory) < 2 {
+		return nil, fmt.Errorf("not enough data points to extrapolate a trend, got %d", len(statusHistory))
+	}
+
+	// x is the offset to now in minutes, so the intercept is the load right now
+	x := make([]float64, len(statusHistory))
+	y := make([]float64, len(statusHistory))
+	for i, status := range statusHistory {
+		x[i] = status.Timestamp.Sub(now).Minutes()
+		y[i] = status.AverageCPUUtilization * float64(status.CurrentActiveReaders)
+	}
+
+	var slope, intercept float64
+	if p.method == TrendMethodTheilSen {
+		slope, intercept = theilSenRegression(x, y)
+	} else {
+		slope, intercept = linearRegression(x, y)
+	}
+
+	load := intercept + slope*horizon.Minutes()
+	readers := float64(statusHistory[len(statusHistory)-1].CurrentActiveReaders)
+
+	return newPredictedStatus(p.config, p.metrics, now.Add(horizon), load, readers), nil
+}
+
+// linearRegression fits y = slope * x + intercept with ordinary least squares
+func linearRegression(x, y []float64) (float64, float64) {
+	meanX, meanY := mean(x), mean(y)
+
+	var covariance, variance float64
+	for i := range x {
+		covariance += (x[i] - meanX) * (y[i] - meanY)
+		variance += (x[i] - meanX) * (x[i] - meanX)
+	}
+
+	if variance == 0 {
+		return 0, meanY
+	}
+
+	slope := covariance / variance
+	return slope, meanY - slope*meanX
+}
+
+// theilSenRegression fits y = slope * x + intercept using the median of all pairwise slopes,
+// which is robust against single spikes in the series
+func theilSenRegression(x, y []float64) (float64, float64) {
+	var slopes []float64
+	for i := 0; i < len(x); i++ {
+		for j := i + 1; j < len(x); j++ {
+			if x[j] != x[i] {
+				slopes = append(slopes, (y[j]-y[i])/(x[j]-x[i]))
+			}
+		}
+	}
+
+	slope := median(slopes)
+
+	residuals := make([]float64, len(x))
+	for i := range x {
+		residuals[i] = y[i] - slope*x[i]
+	}
+	return slope, median(residuals)
+}
@@ -0,0 +1,175 @@
+package scaler
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"predictive-rds-scaler/metrics"
+	"predictive-rds-scaler/types"
+	"time"
+)
+
+// classChange moves a managed reader to another instance class of the vertical scaling ladder
+type classChange struct {
+	identifier    string
+	instanceClass string
+	vcpuDelta     float64
+}
+
+// parseVerticalScalingClasses parses the instance classes managed readers are moved along, from
+// smallest to largest
+func parseVerticalScalingClasses(classesStr string) ([]string, error) {
+	if classesStr == "" {
+		return nil, nil
+	}
+
+	classes := splitAndTrimStrings(classesStr, ",")
+	if len(classes) < 2 {
+		return nil, fmt.Errorf("vertical scaling requires at least two instance classes")
+	}
+
+	var previousVCPUs float64
+	for _, instanceClass := range classes {
+		vcpus, err := metrics.InstanceClassVCPUs(instanceClass)
+		if err != nil {
+			return nil, err
+		}
+		if vcpus <= previousVCPUs {
+			return nil, fmt.Errorf("vertical scaling classes must be ordered from smallest to largest: %s", instanceClass)
+		}
+		previousVCPUs = vcpus
+	}
+	return classes, nil
+}
+
+// nextClassChange finds the managed reader to move one step up (step 1) or down (step -1) the
+// ladder. Scaling up picks the smallest reader, scaling down the largest one.
+func (s *Scaler) nextClassChange(clusterStatus *types.ClusterStatus, step int) (*classChange, bool) {
+	var change *classChange
+	var changeIndex int
+
+	for _, instance := range clusterStatus.Instances {
+		if instance.IsWriter || instance.Status != "available" || !instance.IsManaged {
+			continue
+		}
+
+		index := indexOfString(s.verticalScalingClasses, instance.InstanceClass)
+		next := index + step
+		if index < 0 || next < 0 || next >= len(s.verticalScalingClasses) {
+			continue
+		}
+
+		if change == nil || (step > 0 && index < changeIndex) || (step < 0 && index > changeIndex) {
+			vcpus, _ := metrics.InstanceClassVCPUs(s.verticalScalingClasses[next])
+			change = &classChange{
+				identifier:    instance.Identifier,
+				instanceClass: s.verticalScalingClasses[next],
+				vcpuDelta:     vcpus - instance.VCPUs,
+			}
+			changeIndex = index
+		}
+	}
+	return change, change != nil
+}
+
+// scaleUp moves a managed reader to the next larger class once the reader count is capped and the
+// current or predicted load still exceeds the targets
+func (s *Scaler) scaleUp(now time.Time, clusterStatus *types.ClusterStatus, predictedStatus *types.ClusterStatus) {
+	if !s.metrics.ExceedsTargets(clusterStatus, clusterStatus.CurrentActiveReaders) &&
+		!s.metrics.ExceedsTargets(predictedStatus, clusterStatus.CurrentActiveReaders) {
+		return
+	}
+
+	if s.scalerStatus.IsScaling {
+		s.logger.Info().Msg("Skipping scale up: Scaling operation already in progress")
+		return
+	}
+
+	if s.isScaleOutCoolingDown(now) {
+		s.logger.Info().Time("Timeout", s.scalerStatus.ScaleOutTimeout).Msg("Skipping scale up: Cooldown in progress")
+		return
+	}
+
+	change, ok := s.nextClassChange(clusterStatus, 1)
+	if !ok {
+		s.logger.Info().Msg("Skipping scale up: All managed readers have the largest instance class")
+		return
+	}
+
+	err := s.modifyInstanceClass(change, s.startScaleOutCooldown)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Error scaling up")
+	}
+}
+
+// scaleDown moves a managed reader to the next smaller class if the remaining capacity keeps the
+// current and predicted load below the scale-in target. It returns false if no reader can be
+// scaled down, so readers are removed instead.
+func (s *Scaler) scaleDown(clusterStatus *types.ClusterStatus, predictedStatus *types.ClusterStatus) bool {
+	change, ok := s.nextClassChange(clusterStatus, -1)
+	if !ok {
+		return false
+	}
+
+	scaleInCPUUtilization := s.config.ScaleInCpuUtil
+	if scaleInCPUUtilization == 0 {
+		scaleInCPUUtilization = s.config.TargetCpuUtil
+	}
+
+	if s.metrics.ProjectedCapacityUtilization(clusterStatus, change.vcpuDelta) > scaleInCPUUtilization ||
+		s.metrics.ProjectedCapacityUtilization(predictedStatus, change.vcpuDelta) > scaleInCPUUtilization {
+		return false
+	}
+
+	err := s.modifyInstanceClass(change, s.startScaleInCooldown)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Error scaling down")
+	}
+	return true
+}
+
+// modifyInstanceClass applies the class change immediately and starts the cooldown once the reader
+// is available again
+func (s *Scaler) modifyInstanceClass(change *classChange, startCooldown func(now time.Time)) error {
+	if s.config.DryRun {
+		s.planAction(types.PlannedAction{
+			Action:             ActionModifyInstanceClass,
+			InstanceIdentifier: change.identifier,
+			InstanceClass:      change.instanceClass,
+		})
+		startCooldown(time.Now().In(time.UTC))
+		return nil
+	}
+
+	s.scalerStatus.IsScaling = true
+
+	_, err := s.rdsClient.ModifyDBInstance(&rds.ModifyDBInstanceInput{
+		DBInstanceIdentifier: aws.String(change.identifier),
+		DBInstanceClass:      aws.String(change.instanceClass),
+		ApplyImmediately:     aws.Bool(true),
+	})
+	if err != nil {
+		s.scalerStatus.IsScaling = false
+		return fmt.Errorf("failed to modify instance class of %s: %v", change.identifier, err)
+	}
+
+	s.logger.Info().
+		Str("InstanceIdentifier", change.identifier).
+		Str("InstanceClass", change.instanceClass).
+		Msg("Instance class is modifying")
+
+	go func() {
+		// the modification takes a moment to show up in the instance status
+		time.Sleep(tickInterval)
+		err := s.waitForInstancesAvailable([]string{change.identifier})
+
+		startCooldown(time.Now().In(time.UTC))
+		s.scalerStatus.IsScaling = false
+
+		if err != nil {
+			s.logger.Error().Err(err).Msg("Error waiting for instance to become 'Available'")
+		}
+	}()
+
+	return nil
+}
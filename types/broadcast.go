@@ -2,5 +2,6 @@ package types
 
 type Broadcast struct {
 	MessageType string      `json:"type"`
+	Cluster     string      `json:"cluster,omitempty"`
 	Data        interface{} `json:"data"`
 }
@@ -3,10 +3,13 @@ package types
 import "time"
 
 type ClusterStatus struct {
-	Identifier            string           `json:"identifier"`
-	Timestamp             time.Time        `json:"timestamp"`
-	AverageCPUUtilization float64          `json:"average_cpu_utilization"`
-	CurrentActiveReaders  uint             `json:"current_active_readers"`
-	OptimalSize           uint             `json:"optimal_size"`
-	Instances             []InstanceStatus `json:"instance_status"`
+	Identifier            string             `json:"identifier"`
+	Timestamp             time.Time          `json:"timestamp"`
+	AverageCPUUtilization float64            `json:"average_cpu_utilization"`
+	CurrentActiveReaders  uint               `json:"current_active_readers"`
+	OptimalSize           uint               `json:"optimal_size"`
+	TotalVCPUs            float64            `json:"total_vcpus,omitempty"`
+	WriterCPUUtilization  float64            `json:"writer_cpu_utilization,omitempty"`
+	Metrics               map[string]float64 `json:"metrics,omitempty"`
+	Instances             []InstanceStatus   `json:"instance_status"`
 }
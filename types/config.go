@@ -3,13 +3,51 @@ package types
 import "time"
 
 type Config struct {
-	AwsRegion          string        `json:"aws_region"`
-	RdsClusterName     string        `json:"rds_cluster_name"`
-	InstanceNamePrefix string        `json:"instance_name_prefix"`
-	MaxInstances       uint          `json:"max_instances"`
-	MinInstances       uint          `json:"min_instances"`
-	BoostHours         string        `json:"boost_hours"`
-	TargetCpuUtil      float64       `json:"target_cpu_util"`
-	PlanAheadTime      time.Duration `json:"plan_ahead_time"`
-	ServerPort         uint          `json:"server_port"`
+	AwsRegion                    string        `json:"aws_region" yaml:"aws_region"`
+	RdsClusterName               string        `json:"rds_cluster_name" yaml:"rds_cluster_name"`
+	ClustersFile                 string        `json:"clusters_file" yaml:"-"`
+	DryRun                       bool          `json:"dry_run" yaml:"dry_run"`
+	InstanceNamePrefix           string        `json:"instance_name_prefix" yaml:"instance_name_prefix"`
+	MaxInstances                 uint          `json:"max_instances" yaml:"max_instances"`
+	MinInstances                 uint          `json:"min_instances" yaml:"min_instances"`
+	ExcludeWriter                bool          `json:"exclude_writer" yaml:"exclude_writer"`
+	ReaderInstanceClass          string        `json:"reader_instance_class" yaml:"reader_instance_class"`
+	ReaderInstanceClassFallbacks string        `json:"reader_instance_class_fallbacks" yaml:"reader_instance_class_fallbacks"`
+	VerticalScalingClasses       string        `json:"vertical_scaling_classes" yaml:"vertical_scaling_classes"`
+	CustomEndpoints              string        `json:"custom_endpoints" yaml:"custom_endpoints"`
+	AvailabilityZones            string        `json:"availability_zones" yaml:"availability_zones"`
+	CapacityBlacklistDuration    time.Duration `json:"capacity_blacklist_duration" yaml:"capacity_blacklist_duration"`
+	PromotionTier                int64         `json:"promotion_tier" yaml:"promotion_tier"`
+	FailoverAction               string        `json:"failover_action" yaml:"failover_action"`
+	ScalingMode                  string        `json:"scaling_mode" yaml:"scaling_mode"`
+	ServerlessMinCapacity        float64       `json:"serverless_min_capacity" yaml:"serverless_min_capacity"`
+	BurstDuration                time.Duration `json:"burst_duration" yaml:"burst_duration"`
+	BoostHours                   string        `json:"boost_hours" yaml:"boost_hours"`
+	ScheduleFile                 string        `json:"schedule_file" yaml:"schedule_file"`
+	TargetCpuUtil                float64       `json:"target_cpu_util" yaml:"target_cpu_util"`
+	ScaleInCpuUtil               float64       `json:"scale_in_cpu_util" yaml:"scale_in_cpu_util"`
+	TargetConnections            float64       `json:"target_connections" yaml:"target_connections"`
+	TargetReplicaLag             float64       `json:"target_replica_lag" yaml:"target_replica_lag"`
+	TargetReadIOPS               float64       `json:"target_read_iops" yaml:"target_read_iops"`
+	TargetDBLoad                 float64       `json:"target_db_load" yaml:"target_db_load"`
+	PlanAheadTime                time.Duration `json:"plan_ahead_time" yaml:"plan_ahead_time"`
+	ScaleOutCooldown             time.Duration `json:"scale_out_cooldown" yaml:"scale_out_cooldown"`
+	ScaleInCooldown              time.Duration `json:"scale_in_cooldown" yaml:"scale_in_cooldown"`
+	ScaleInStabilizationWindow   time.Duration `json:"scale_in_stabilization_window" yaml:"scale_in_stabilization_window"`
+	TimeZone                     string        `json:"time_zone" yaml:"time_zone"`
+	Predictor                    string        `json:"predictor" yaml:"predictor"`
+	SeasonalWeeks                uint          `json:"seasonal_weeks" yaml:"seasonal_weeks"`
+	SeasonalWeights              string        `json:"seasonal_weights" yaml:"seasonal_weights"`
+	SeasonalAggregation          string        `json:"seasonal_aggregation" yaml:"seasonal_aggregation"`
+	HoltWintersHistory           time.Duration `json:"holt_winters_history" yaml:"holt_winters_history"`
+	HoltWintersAlpha             float64       `json:"holt_winters_alpha" yaml:"holt_winters_alpha"`
+	HoltWintersBeta              float64       `json:"holt_winters_beta" yaml:"holt_winters_beta"`
+	HoltWintersGamma             float64       `json:"holt_winters_gamma" yaml:"holt_winters_gamma"`
+	HoltWintersDelta             float64       `json:"holt_winters_delta" yaml:"holt_winters_delta"`
+	TrendWindow                  time.Duration `json:"trend_window" yaml:"trend_window"`
+	TrendMethod                  string        `json:"trend_method" yaml:"trend_method"`
+	EnsemblePredictors           string        `json:"ensemble_predictors" yaml:"ensemble_predictors"`
+	EnsembleWindow               time.Duration `json:"ensemble_window" yaml:"ensemble_window"`
+	CalendarFile                 string        `json:"calendar_file" yaml:"calendar_file"`
+	ServerPort                   uint          `json:"server_port" yaml:"-"`
 }
@@ -3,8 +3,31 @@ package types
 import "time"
 
 type Cooldown struct {
-	LastScale time.Time `json:"last_scale"`
-	Timeout   time.Time `json:"timeout"`
-	IsScaling bool      `json:"is_scaling"`
-	Threshold uint
+	LastScale             time.Time              `json:"last_scale"`
+	ScaleOutTimeout       time.Time              `json:"scale_out_timeout"` // no scale out before this time
+	ScaleInTimeout        time.Time              `json:"scale_in_timeout"`  // no scale in before this time
+	IsScaling             bool                   `json:"is_scaling"`
+	Threshold             uint                   `json:"threshold"`                         // highest desired size within the stabilization window, scale in never goes below it
+	IsStable              bool                   `json:"is_stable"`                         // whether the desired size has been observed for the whole stabilization window
+	ServerlessMinCapacity float64                `json:"serverless_min_capacity,omitempty"` // minimum ACUs per Serverless v2 instance required by the forecast
+	UnavailablePlacements []UnavailablePlacement `json:"unavailable_placements,omitempty"`  // instance classes and AZs that ran out of capacity
+	LastPlacement         *ReaderPlacement       `json:"last_placement,omitempty"`          // instance class and AZ of the last created reader
+}
+
+// ReaderPlacement is the instance class and AZ a reader was created with
+type ReaderPlacement struct {
+	Timestamp                 time.Time `json:"timestamp"`
+	InstanceIdentifier        string    `json:"instance_identifier"`
+	InstanceClass             string    `json:"instance_class"`
+	AvailabilityZone          string    `json:"availability_zone,omitempty"`
+	PreferredInstanceClass    string    `json:"preferred_instance_class"`
+	PreferredAvailabilityZone string    `json:"preferred_availability_zone,omitempty"`
+	IsFallback                bool      `json:"is_fallback"`
+}
+
+// UnavailablePlacement is an instance class and AZ new readers aren't created in until the given time
+type UnavailablePlacement struct {
+	InstanceClass    string    `json:"instance_class"`
+	AvailabilityZone string    `json:"availability_zone,omitempty"`
+	Until            time.Time `json:"until"`
 }
@@ -1,8 +1,12 @@
 package types
 
 type InstanceStatus struct {
-	Identifier     string  `json:"identifier"`
-	IsWriter       bool    `json:"is_writer"`
-	Status         string  `json:"status"`
-	CPUUtilization float64 `json:"cpu_utilization"`
+	Identifier     string             `json:"identifier"`
+	IsWriter       bool               `json:"is_writer"`
+	IsManaged      bool               `json:"is_managed"` // created and owned by the scaler, only these are scaled
+	Status         string             `json:"status"`
+	InstanceClass  string             `json:"instance_class"`
+	VCPUs          float64            `json:"vcpus"`
+	CPUUtilization float64            `json:"cpu_utilization"`
+	Metrics        map[string]float64 `json:"metrics,omitempty"`
 }
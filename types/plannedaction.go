@@ -0,0 +1,13 @@
+package types
+
+import "time"
+
+// PlannedAction is a change the scaler decided on but skipped in dry-run mode
+type PlannedAction struct {
+	Timestamp          time.Time `json:"timestamp"`
+	Action             string    `json:"action"`
+	InstanceIdentifier string    `json:"instance_identifier,omitempty"`
+	InstanceClass      string    `json:"instance_class,omitempty"`
+	Count              uint      `json:"count,omitempty"`
+	MinCapacity        float64   `json:"min_capacity,omitempty"`
+}
@@ -0,0 +1,13 @@
+package types
+
+import "time"
+
+type PredictionAccuracy struct {
+	Predictor                string        `json:"predictor"`
+	Window                   time.Duration `json:"window"`
+	Samples                  uint          `json:"samples"`
+	MeanAbsoluteError        float64       `json:"mean_absolute_error"`
+	MeanAbsolutePercentError float64       `json:"mean_absolute_percent_error"`
+	UnderProvisionedMinutes  float64       `json:"under_provisioned_minutes"`
+	OverProvisionedMinutes   float64       `json:"over_provisioned_minutes"`
+}
@@ -2,7 +2,7 @@ import {useEffect, useState} from 'react';
 import {createTheme, ThemeProvider} from '@mui/material/styles';
 import CssBaseline from '@mui/material/CssBaseline';
 import Box from '@mui/material/Box';
-import {AppBar, Badge, Container, Grid, IconButton, Paper, Toolbar, Typography} from "@mui/material";
+import {AppBar, Badge, Container, Grid, IconButton, MenuItem, Paper, Select, Toolbar, Typography} from "@mui/material";
 
 import NotificationsIcon from '@mui/icons-material/Notifications';
 import MenuIcon from '@mui/icons-material/Menu';
@@ -15,6 +15,8 @@ import CurrentSize from "./components/CurrentSize.tsx";
 import ClusterMap from "./components/ClusterMap.tsx";
 import GraphUtilization from "./components/GraphUtilization.tsx";
 import GraphClusterSize from "./components/GraphClusterSize.tsx";
+import Placements from "./components/Placements.tsx";
+import AccuracyTable from "./components/AccuracyTable.tsx";
 import Broadcast from "./types/Broadcast.ts";
 
 const theme = createTheme({
@@ -27,12 +29,12 @@ const theme = createTheme({
 });
 
 function App() {
-    const socketUrl =
-        'ws://' +
-        (process.env.NODE_ENV === 'development'
+    const host =
+        process.env.NODE_ENV === 'development'
             ? 'localhost:8041/'
-            : 'localhost:8001/api/v1/namespaces/kube-system/services/http:rds-predictive-scaler:http/proxy/') +
-        'ws';
+            : 'localhost:8001/api/v1/namespaces/kube-system/services/http:rds-predictive-scaler:http/proxy/';
+    const socketUrl = 'ws://' + host + 'ws';
+    const apiUrl = 'http://' + host + 'api/clusters/';
 
     const {lastMessage, readyState} = useWebSocket(socketUrl, {
         onOpen: () => {
@@ -43,11 +45,16 @@ function App() {
 
     const [appBarOpen, setAppBarOpen] = useState(true);
 
+    const [cluster, setCluster] = useState<string | null>(null);
+    const [configs, setConfigs] = useState<Record<string, Config>>({});
     const [config, setConfig] = useState<Config | null>(null);
     const [clusterStatus, setClusterStatus] = useState<ClusterStatus | null>(null);
     const [clusterStatusPrediction, setClusterStatusPrediction] = useState<ClusterStatus | null>(null);
     const [clusterStatusHistory, setClusterStatusHistory] = useState<ClusterStatus[]>([]);
     const [clusterStatusPredictionHistory, setClusterStatusPredictionHistory] = useState<ClusterStatus[]>([]);
+    const [plannedActions, setPlannedActions] = useState<PlannedAction[]>([]);
+    const [scalerStatus, setScalerStatus] = useState<Cooldown | null>(null);
+    const [predictionAccuracy, setPredictionAccuracy] = useState<PredictionAccuracy[]>([]);
 
     const toggleDrawer = () => {
         setAppBarOpen(!appBarOpen);
@@ -57,8 +64,18 @@ function App() {
         if (lastMessage === null) return;
         const broadcast = JSON.parse(lastMessage.data) as Broadcast;
 
+        if (broadcast.type === 'config' && broadcast.cluster !== undefined) {
+            setConfigs((prev) => ({...prev, [broadcast.cluster as string]: broadcast.data}));
+        }
+
+        // the dashboard follows the selected cluster, by default the first one it receives the config of
+        if (cluster !== null && broadcast.cluster !== undefined && broadcast.cluster !== cluster) return;
+
         switch (broadcast.type) {
             case 'config':
+                if (cluster === null && broadcast.cluster !== undefined) {
+                    setCluster(broadcast.cluster);
+                }
                 setConfig(broadcast.data);
                 break;
             case 'clusterStatus':
@@ -79,9 +96,36 @@ function App() {
             case 'clusterStatusHistory':
                 setClusterStatusHistory(broadcast.data);
                 break;
+            case 'predictionAccuracy':
+                setPredictionAccuracy(broadcast.data ?? []);
+                break;
+            case 'scalerStatus':
+                setScalerStatus(broadcast.data);
+                break;
+            case 'plannedAction':
+                // actions skipped in dry-run mode, the most recent ones are kept
+                setPlannedActions((prev) => [...prev.slice(-49), broadcast.data]);
+                break;
         }
     }, [lastMessage]);
 
+    // the broadcasts only carry the history on connect, a newly selected cluster loads it from the API
+    const selectCluster = (name: string) => {
+        const fetchJSON = (path: string) =>
+            fetch(apiUrl + encodeURIComponent(name) + path).then((response) => response.json());
+
+        setCluster(name);
+        setConfig(configs[name] ?? null);
+        setClusterStatusPrediction(null);
+        setScalerStatus(null);
+        setPlannedActions([]);
+
+        fetchJSON('/status').then(setClusterStatus);
+        fetchJSON('/history').then((data) => setClusterStatusHistory(data ?? []));
+        fetchJSON('/predictionHistory').then((data) => setClusterStatusPredictionHistory(data ?? []));
+        fetchJSON('/accuracy').then((data) => setPredictionAccuracy(data ?? []));
+    };
+
     const aggregatedHistory = groupDataByTime(clusterStatusHistory, 5 * 60 * 1000);
     const aggregatedPredictionHistory = groupDataByTime(clusterStatusPredictionHistory, 5 * 60 * 1000);
 
@@ -117,8 +161,33 @@ function App() {
                             >
                                 ScaleAI RDS Predictive Scaler
                             </Typography>
-                            <IconButton color="inherit">
-                                <Badge badgeContent={4} color="secondary">
+                            {Object.keys(configs).length > 1 && (
+                                <Select
+                                    value={cluster ?? ''}
+                                    onChange={(event) => selectCluster(event.target.value)}
+                                    variant="standard"
+                                    size="small"
+                                    sx={{marginRight: '12px', color: 'inherit'}}
+                                >
+                                    {Object.keys(configs).map((name) => (
+                                        <MenuItem key={name} value={name}>{name}</MenuItem>
+                                    ))}
+                                </Select>
+                            )}
+                            {config?.dry_run && (
+                                <Typography variant="subtitle2" color="inherit" noWrap sx={{marginRight: '12px'}}>
+                                    Dry run
+                                </Typography>
+                            )}
+                            <IconButton
+                                color="inherit"
+                                title={plannedActions.map((plannedAction) =>
+                                    [plannedAction.action, plannedAction.count, plannedAction.instance_identifier, plannedAction.instance_class, plannedAction.min_capacity]
+                                        .filter((value) => value !== undefined && value !== '')
+                                        .join(' ')
+                                ).join('\n')}
+                            >
+                                <Badge badgeContent={plannedActions.length} color="secondary">
                                     <NotificationsIcon/>
                                 </Badge>
                             </IconButton>
@@ -175,6 +244,22 @@ function App() {
                                     </Paper>
                                 </Grid>
 
+                                {(scalerStatus?.last_placement || scalerStatus?.unavailable_placements) && (
+                                    <Grid item xs={12}>
+                                        <Paper sx={{p: 2, display: 'flex', flexDirection: 'column'}}>
+                                            <Placements scalerStatus={scalerStatus}/>
+                                        </Paper>
+                                    </Grid>
+                                )}
+
+                                {predictionAccuracy.length > 0 && (
+                                    <Grid item xs={12}>
+                                        <Paper sx={{p: 2, display: 'flex', flexDirection: 'column'}}>
+                                            <AccuracyTable accuracy={predictionAccuracy}/>
+                                        </Paper>
+                                    </Grid>
+                                )}
+
                                 <Grid item xs={12}>
                                     <ClusterMap clusterStatus={clusterStatus}/>
                                 </Grid>
@@ -0,0 +1,45 @@
+import React from 'react';
+import {Box, Table, TableBody, TableCell, TableHead, TableRow, Typography} from '@mui/material';
+
+interface AccuracyTableProps {
+    accuracy: PredictionAccuracy[];
+}
+
+// durations arrive as nanoseconds
+const nanosecondsPerHour = 3600 * 1e9;
+
+const AccuracyTable: React.FC<AccuracyTableProps> = ({accuracy}) => {
+    return (
+        <Box>
+            <Typography variant={"h6"}>Prediction Accuracy</Typography>
+            <Table size="small">
+                <TableHead>
+                    <TableRow>
+                        <TableCell>Predictor</TableCell>
+                        <TableCell align="right">Window</TableCell>
+                        <TableCell align="right">Samples</TableCell>
+                        <TableCell align="right">MAE</TableCell>
+                        <TableCell align="right">MAPE</TableCell>
+                        <TableCell align="right">Under-provisioned</TableCell>
+                        <TableCell align="right">Over-provisioned</TableCell>
+                    </TableRow>
+                </TableHead>
+                <TableBody>
+                    {accuracy.map((predictionAccuracy) => (
+                        <TableRow key={predictionAccuracy.predictor}>
+                            <TableCell>{predictionAccuracy.predictor}</TableCell>
+                            <TableCell align="right">{(predictionAccuracy.window / nanosecondsPerHour).toFixed(0)}h</TableCell>
+                            <TableCell align="right">{predictionAccuracy.samples}</TableCell>
+                            <TableCell align="right">{predictionAccuracy.mean_absolute_error.toFixed(2)}</TableCell>
+                            <TableCell align="right">{predictionAccuracy.mean_absolute_percent_error.toFixed(1)}%</TableCell>
+                            <TableCell align="right">{predictionAccuracy.under_provisioned_minutes.toFixed(0)} min</TableCell>
+                            <TableCell align="right">{predictionAccuracy.over_provisioned_minutes.toFixed(0)} min</TableCell>
+                        </TableRow>
+                    ))}
+                </TableBody>
+            </Table>
+        </Box>
+    );
+};
+
+export default AccuracyTable;
\ No newline at end of file
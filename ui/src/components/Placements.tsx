@@ -0,0 +1,43 @@
+import React from 'react';
+import {Box, Chip, Stack, Typography} from '@mui/material';
+
+interface PlacementsProps {
+    scalerStatus: Cooldown;
+}
+
+const placementName = (instanceClass: string, availabilityZone?: string) =>
+    availabilityZone ? `${instanceClass} in ${availabilityZone}` : instanceClass;
+
+const Placements: React.FC<PlacementsProps> = ({scalerStatus}) => {
+    const lastPlacement = scalerStatus.last_placement;
+    // placements are only pruned on the next capacity error, hide the ones that expired since
+    const unavailablePlacements = (scalerStatus.unavailable_placements || [])
+        .filter((placement) => new Date(placement.until).getTime() > Date.now());
+
+    return (
+        <Box>
+            <Typography variant={"h6"}>Placement</Typography>
+            {lastPlacement && (
+                <Typography variant="body1" color={lastPlacement.is_fallback ? "warning.main" : "inherit"}>
+                    {lastPlacement.instance_identifier}: {placementName(lastPlacement.instance_class, lastPlacement.availability_zone)}
+                    {lastPlacement.is_fallback &&
+                        ` (fallback from ${placementName(lastPlacement.preferred_instance_class, lastPlacement.preferred_availability_zone)})`}
+                </Typography>
+            )}
+            {unavailablePlacements.length > 0 && (
+                <Stack direction="row" spacing={1} sx={{pt: 1, flexWrap: 'wrap'}}>
+                    {unavailablePlacements.map((placement) => (
+                        <Chip
+                            key={placement.instance_class + placement.availability_zone}
+                            color="error"
+                            variant="outlined"
+                            label={`${placementName(placement.instance_class, placement.availability_zone)} out of capacity until ${new Date(placement.until).toLocaleTimeString()}`}
+                        />
+                    ))}
+                </Stack>
+            )}
+        </Box>
+    );
+};
+
+export default Placements;
\ No newline at end of file
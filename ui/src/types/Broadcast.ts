@@ -1,5 +1,6 @@
 interface Broadcast {
     type: string;
+    cluster?: string;
     data: any
 }
 
@@ -4,5 +4,8 @@ interface ClusterStatus {
     average_cpu_utilization: number;
     current_active_readers: number;
     optimal_size: number;
+    total_vcpus?: number;
+    writer_cpu_utilization?: number;
+    metrics?: Record<string, number>;
     instance_status: InstanceStatus[];
 }
\ No newline at end of file
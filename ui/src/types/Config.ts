@@ -1,11 +1,49 @@
 interface Config {
     aws_region: string;
     rds_cluster_name: string;
+    clusters_file: string;
+    dry_run: boolean;
     instance_name_prefix: string;
     max_instances: number;
     min_instances: number;
+    exclude_writer: boolean;
+    reader_instance_class: string;
+    reader_instance_class_fallbacks: string;
+    vertical_scaling_classes: string;
+    custom_endpoints: string;
+    availability_zones: string;
+    capacity_blacklist_duration: number;
+    promotion_tier: number;
+    failover_action: string;
+    scaling_mode: string;
+    serverless_min_capacity: number;
+    burst_duration: number;
     boost_hours: string;
+    schedule_file: string;
     target_cpu_util: number;
+    scale_in_cpu_util: number;
+    target_connections: number;
+    target_replica_lag: number;
+    target_read_iops: number;
+    target_db_load: number;
     plan_ahead_time: number;
+    scale_out_cooldown: number;
+    scale_in_cooldown: number;
+    scale_in_stabilization_window: number;
+    time_zone: string;
+    predictor: string;
+    seasonal_weeks: number;
+    seasonal_weights: string;
+    seasonal_aggregation: string;
+    holt_winters_history: number;
+    holt_winters_alpha: number;
+    holt_winters_beta: number;
+    holt_winters_gamma: number;
+    holt_winters_delta: number;
+    trend_window: number;
+    trend_method: string;
+    ensemble_predictors: string;
+    ensemble_window: number;
+    calendar_file: string;
     server_port: number;
 }
\ No newline at end of file
@@ -0,0 +1,27 @@
+interface Cooldown {
+    last_scale: Date;
+    scale_out_timeout: Date;
+    scale_in_timeout: Date;
+    is_scaling: boolean;
+    threshold: number;
+    is_stable: boolean;
+    serverless_min_capacity?: number;
+    unavailable_placements?: UnavailablePlacement[];
+    last_placement?: ReaderPlacement;
+}
+
+interface ReaderPlacement {
+    timestamp: Date;
+    instance_identifier: string;
+    instance_class: string;
+    availability_zone?: string;
+    preferred_instance_class: string;
+    preferred_availability_zone?: string;
+    is_fallback: boolean;
+}
+
+interface UnavailablePlacement {
+    instance_class: string;
+    availability_zone?: string;
+    until: Date;
+}
\ No newline at end of file
@@ -1,6 +1,10 @@
 interface InstanceStatus {
     identifier: string;
     is_writer: boolean;
+    is_managed: boolean;
     status: string;
+    instance_class: string;
+    vcpus: number;
     cpu_utilization: number;
+    metrics?: Record<string, number>;
 }
\ No newline at end of file
@@ -0,0 +1,8 @@
+interface PlannedAction {
+    timestamp: Date;
+    action: string;
+    instance_identifier?: string;
+    instance_class?: string;
+    count?: number;
+    min_capacity?: number;
+}
\ No newline at end of file
@@ -0,0 +1,9 @@
+interface PredictionAccuracy {
+    predictor: string;
+    window: number;
+    samples: number;
+    mean_absolute_error: number;
+    mean_absolute_percent_error: number;
+    under_provisioned_minutes: number;
+    over_provisioned_minutes: number;
+}
\ No newline at end of file